@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"swarm"
+	"swarm/internal"
+	"swarm/pkg"
+)
+
+// export messages of a stream without the terminal interface, e.g.
+//   export -stream orders -format csv -from "2020-01-02 15:00:00" -filter "status=failed" -out orders.csv
+func main() {
+	stream := flag.String("stream", "", "name of exported stream (required)")
+	format := flag.String("format", pkg.ExportNDJSON, "export format: "+strings.Join(pkg.ExportFormats, ", "))
+	from := flag.String("from", "", "lowest exported message ID or date time, the start of the stream when empty")
+	to := flag.String("to", "", "highest exported message ID or date time, the end of the stream when empty")
+	query := flag.String("filter", "", "filter of exported messages")
+	out := flag.String("out", "", "output file, standard output when empty")
+	flag.Parse()
+
+	if *stream == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*stream, *format, *from, *to, *query, *out); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(stream, format, from, to, query, out string) error {
+	config := swarm.Config()
+	o := pkg.ExportOptions{Stream: stream, Format: format}
+
+	var err error
+	if o.From, err = pkg.ParseRangeBound(from, false); err != nil {
+		return err
+	}
+	if o.To, err = pkg.ParseRangeBound(to, true); err != nil {
+		return err
+	}
+
+	if query != "" {
+		decoders, err := internal.NewDecoders(config)
+		if err != nil {
+			return fmt.Errorf("invalid decoders configuration, err: %v", err)
+		}
+
+		if o.Filter, err = pkg.ParseFilter(query, decoders); err != nil {
+			return err
+		}
+	}
+
+	client := internal.NewRedisClient(config)
+	defer client.Close()
+	if _, err := client.Ping().Result(); err != nil {
+		return fmt.Errorf("failed to connect with Redis, err: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	var f *os.File
+	if out != "" {
+		if f, err = os.Create(out); err != nil {
+			return err
+		}
+		w = f
+	}
+
+	n, err := pkg.NewMonitor(client).Export(w, o)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Exported %d message(s) of %s stream\n", n, stream)
+
+	return nil
+}
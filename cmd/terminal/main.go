@@ -1,24 +1,30 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"github.com/go-redis/redis"
 	"github.com/rivo/tview"
+	"os"
+	"os/signal"
 	"swarm"
 	"swarm/internal"
 	"swarm/pkg"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
-	config := swarm.Config()
+	readOnly := flag.Bool("read-only", false, "disable listening and all writes to Redis, overrides read_only configuration")
+	flag.Parse()
 
-	client := redis.NewClient(&redis.Options{
-		Addr:        fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
-		Password:    config.RedisPassword,
-		DB:          0,
-		ReadTimeout: -1,
-	})
+	config := swarm.Config()
+	if *readOnly {
+		config.ReadOnly = true
+	}
 
+	client := internal.NewRedisClient(config)
 	_, err := client.Ping().Result()
 	if err != nil {
 		panic(fmt.Sprintf("failed to connect with Redis, err: %v", err))
@@ -26,19 +32,102 @@ func main() {
 
 	app := tview.NewApplication()
 	monitor := pkg.NewMonitor(client)
-	listener, err := pkg.NewListener()
+	monitor.Discovery = config.Discovery
+	monitor.ScanCount = config.ScanCount
+	monitor.ScanInterval = time.Millisecond * time.Duration(config.ScanInterval)
+	monitor.ScanCacheSize = config.ScanCacheSize
+	monitor.MessagesLimit = config.MessagesLimit
+	monitor.MemoryBudget = config.MemoryBudget * 1024 * 1024
+	monitor.Readers = config.Readers
+	monitor.ReadBlock = time.Millisecond * time.Duration(config.ReadBlock)
+	monitor.RetryMax = time.Millisecond * time.Duration(config.RetryMax)
+	monitor.LifecycleInterval = time.Millisecond * time.Duration(config.LifecycleInterval)
+	monitor.DeletedRetention = time.Millisecond * time.Duration(config.DeletedRetention)
+	monitor.GroupsInterval = time.Millisecond * time.Duration(config.GroupsInterval)
+	decoders, err := internal.NewDecoders(config)
+	if err != nil {
+		panic(fmt.Sprintf("invalid decoders configuration, err: %v", err))
+	}
+
+	monitor.ReadOnly = config.ReadOnly
+	monitor.Decoders = decoders
+	monitor.Audit = pkg.NewAuditLog(config.AuditLog, fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort))
+	monitor.WatchCommand = config.WatchCommand
+	for _, query := range config.Watches {
+		if _, err := monitor.AddWatch(query); err != nil {
+			panic(fmt.Sprintf("invalid watch %q, err: %v", query, err))
+		}
+	}
+
+	var listener *pkg.Listener
+	err = pkg.ErrReadOnly
+	if !config.ReadOnly {
+		listener, err = pkg.NewListener()
+	}
+
+	if err == nil {
+		if config.ListenerGroup != "" {
+			listener.Group = config.ListenerGroup
+		}
+		if config.ListenerConsumer != "" {
+			listener.Consumer = config.ListenerConsumer
+		}
+		listener.Copy = config.ListenerCopyPrefix != ""
+		listener.Monitor = monitor
+		if config.ListenerCopyPrefix != "" {
+			monitor.CopyStreams(config.ListenerCopyPrefix)
+		}
+	}
+
 	terminal := internal.NewTerminal(app, err == nil)
+	terminal.HistoryPage = config.HistoryPage
+	terminal.Decoders = decoders
+	terminal.DashboardInterval = time.Millisecond * time.Duration(config.DashboardInterval)
+	terminal.Profiles = make(map[string]*pkg.Monitor)
+	for name := range config.Profiles {
+		profile, _ := config.Profile(name)
+		terminal.Profiles[name] = pkg.NewMonitor(internal.NewRedisClient(profile))
+		terminal.Profiles[name].ReadOnly = config.ReadOnly
+	}
+	if config.ReadOnly {
+		terminal.SetReadOnly()
+	}
 	terminal.BindMonitor(monitor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var once sync.Once
+	shutdown := func() {
+		once.Do(func() {
+			cancel()
+			monitor.Stop()
+			if listener != nil {
+				listener.Stop()
+				listener.Cleanup()
+			}
+			terminal.Stop()
+			app.Stop()
+		})
+	}
+	terminal.OnQuit(shutdown)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		shutdown()
+	}()
+
 	if err == nil {
 		terminal.BindListener(listener)
-		listener.StartListening()
+		listener.Start(ctx)
 	} else {
 		pkg.LogWarning(err.Error())
 	}
 
-	go monitor.StartMonitoring()
+	monitor.Start(ctx)
 
 	if err := app.SetRoot(terminal.Layout, true).Run(); err != nil {
+		shutdown()
 		panic(err)
 	}
 }
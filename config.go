@@ -3,23 +3,57 @@ package swarm
 import (
 	"encoding/json"
 	"io/ioutil"
-	"os"
 )
 
-// Config of the Monitor
+// Config of the Monitor read from config.json in the working directory.
 func Config() Configuration {
-	jsonFile, err := os.Open("config.json")
-	config := Configuration{
-		RedisHost: "localhost",
-		RedisPort: 6379,
+	return ConfigFile("config.json")
+}
+
+// ConfigFile reads configuration from a JSON file. Missing values, and intervals
+// that are not positive, are set to their defaults.
+func ConfigFile(path string) Configuration {
+	defaults := Configuration{
+		RedisHost:         "localhost",
+		RedisPort:         6379,
+		Discovery:         "polling",
+		ScanCount:         1000,
+		ScanInterval:      250,
+		ScanCacheSize:     10000,
+		LifecycleInterval: 5000,
+		DeletedRetention:  300000,
+		GroupsInterval:    5000,
+		MessagesLimit:     1000,
+		HistoryPage:       100,
+		DashboardInterval: 5000,
+		Readers:           2,
+		ReadBlock:         1000,
+		RetryMax:          30000,
+		MemoryBudget:      256,
+		AuditLog:          "swarm-audit.log",
 	}
 
-	if err == nil {
-		defer jsonFile.Close()
-		bytes, _ := ioutil.ReadAll(jsonFile)
+	config := defaults
+	if bytes, err := ioutil.ReadFile(path); err == nil {
 		_ = json.Unmarshal(bytes, &config)
 	}
 
+	for _, interval := range []struct {
+		value    *int
+		fallback int
+	}{
+		{&config.ScanInterval, defaults.ScanInterval},
+		{&config.LifecycleInterval, defaults.LifecycleInterval},
+		{&config.GroupsInterval, defaults.GroupsInterval},
+		{&config.DashboardInterval, defaults.DashboardInterval},
+		{&config.ReadBlock, defaults.ReadBlock},
+		{&config.RetryMax, defaults.RetryMax},
+	} {
+		if *interval.value <= 0 {
+			*interval.value = interval.fallback
+		}
+	}
+
 	return config
 }
 
@@ -29,4 +63,97 @@ type Configuration struct {
 	RedisPort     int    `json:"redis_port,omitempty"`
 	RedisPassword string `json:"redis_password,omitempty"`
 	ArtisanPath   string `json:"artisan_path,omitempty"`
+	// ListenerGroup is a consumer group name of listeners, unique per swarm instance when empty.
+	ListenerGroup string `json:"listener_group,omitempty"`
+	// ListenerConsumer is a consumer name of listeners, unique per swarm instance when empty.
+	ListenerConsumer string `json:"listener_consumer,omitempty"`
+	// ListenerCopyPrefix makes listeners read copies of streams named with this prefix and instance name,
+	// streams are read directly when empty.
+	ListenerCopyPrefix string `json:"listener_copy_prefix,omitempty"`
+	// ReadOnly disables listening and all writes to Redis, only reading commands are used.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// Discovery of new streams, either "polling" or "notifications".
+	Discovery string `json:"discovery,omitempty"`
+	// ScanCount is a COUNT hint used for every SCAN call looking for streams.
+	ScanCount int64 `json:"scan_count,omitempty"`
+	// ScanInterval in milliseconds between consecutive SCAN calls.
+	ScanInterval int `json:"scan_interval,omitempty"`
+	// ScanCacheSize limits how many non-stream keys are remembered
+	// when Redis does not support SCAN with TYPE option.
+	ScanCacheSize int `json:"scan_cache_size,omitempty"`
+	// LifecycleInterval in milliseconds between checks of streams
+	// being deleted, recreated or trimmed.
+	LifecycleInterval int `json:"lifecycle_interval,omitempty"`
+	// DeletedRetention in milliseconds is how long deleted streams are shown before they are forgotten.
+	DeletedRetention int `json:"deleted_retention,omitempty"`
+	// GroupsInterval in milliseconds between collections of consumer groups of all streams.
+	GroupsInterval int `json:"groups_interval,omitempty"`
+	// MessagesLimit of the most recent messages kept in memory per stream.
+	MessagesLimit int `json:"messages_limit,omitempty"`
+	// HistoryPage is a number of older messages loaded at once when scrolling past the top of messages list.
+	HistoryPage int64 `json:"history_page,omitempty"`
+	// DashboardInterval in milliseconds between dashboard refreshes.
+	DashboardInterval int `json:"dashboard_interval,omitempty"`
+	// Readers is a number of loops reading new messages of all streams with multi-key XREAD.
+	Readers int `json:"readers,omitempty"`
+	// ReadBlock in milliseconds is the longest time a single XREAD call waits for new messages.
+	ReadBlock int `json:"read_block,omitempty"`
+	// RetryMax in milliseconds is the longest delay between retries of failed reads.
+	RetryMax int `json:"retry_max,omitempty"`
+	// MemoryBudget in megabytes for messages of all streams.
+	MemoryBudget int64 `json:"memory_budget,omitempty"`
+	// Decoders rules select decoders of message fields values per stream and field,
+	// fields not matching any rule are decoded automatically.
+	Decoders []DecoderRule `json:"decoders,omitempty"`
+	// ProtobufDescriptors is a path of compiled FileDescriptorSet with protobuf message types.
+	ProtobufDescriptors string `json:"protobuf_descriptors,omitempty"`
+	// Protobuf rules map fields of streams to protobuf message types, before Decoders rules are checked.
+	Protobuf []ProtobufRule `json:"protobuf,omitempty"`
+	// Watches are filters of messages reported as soon as they are read.
+	Watches []string `json:"watches,omitempty"`
+	// WatchCommand is a shell command run for every watch match, with message JSON on its standard input.
+	WatchCommand string `json:"watch_command,omitempty"`
+	// AuditLog is a path of the file destructive commands are recorded in.
+	AuditLog string `json:"audit_log,omitempty"`
+	// Profiles of other Redis connections messages can be republished to, by name.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Profile of Redis connection, host and port default to localhost:6379.
+type Profile struct {
+	RedisHost     string `json:"redis_host,omitempty"`
+	RedisPort     int    `json:"redis_port,omitempty"`
+	RedisPassword string `json:"redis_password,omitempty"`
+}
+
+// Profile returns configuration with Redis connection of a named profile.
+func (c Configuration) Profile(name string) (Configuration, bool) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return c, false
+	}
+
+	c.RedisHost, c.RedisPort, c.RedisPassword = "localhost", 6379, p.RedisPassword
+	if p.RedisHost != "" {
+		c.RedisHost = p.RedisHost
+	}
+	if p.RedisPort != 0 {
+		c.RedisPort = p.RedisPort
+	}
+
+	return c, true
+}
+
+// DecoderRule selects decoders of message fields values by stream and field name patterns.
+type DecoderRule struct {
+	Stream   string   `json:"stream,omitempty"`
+	Field    string   `json:"field,omitempty"`
+	Decoders []string `json:"decoders"`
+}
+
+// ProtobufRule maps message fields values to protobuf message type by stream and field name patterns.
+type ProtobufRule struct {
+	Stream  string `json:"stream,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,42 @@
+package swarm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	content := `{"scan_interval": 0, "lifecycle_interval": -1, "groups_interval": 0, "dashboard_interval": 0,
+		"read_block": 0, "retry_max": 0, "messages_limit": 50}`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ConfigFile(path)
+	for name, tt := range map[string]struct{ got, want int }{
+		"scan_interval":      {got.ScanInterval, 250},
+		"lifecycle_interval": {got.LifecycleInterval, 5000},
+		"groups_interval":    {got.GroupsInterval, 5000},
+		"dashboard_interval": {got.DashboardInterval, 5000},
+		"read_block":         {got.ReadBlock, 1000},
+		"retry_max":          {got.RetryMax, 30000},
+		"messages_limit":     {got.MessagesLimit, 50},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("ConfigFile() %s = %d, want %d", name, tt.got, tt.want)
+		}
+	}
+
+	if got := ConfigFile(filepath.Join(dir, "missing.json")); got.RedisPort != 6379 {
+		t.Errorf("ConfigFile() of missing file RedisPort = %d, want defaults", got.RedisPort)
+	}
+}
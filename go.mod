@@ -7,4 +7,6 @@ require (
 	github.com/gdamore/tcell v1.1.2
 	github.com/go-redis/redis v6.15.5+incompatible
 	github.com/rivo/tview v0.0.0-20190829161255-f8bc69b90341
+	github.com/vmihailenco/msgpack v4.0.4+incompatible
+	google.golang.org/protobuf v1.28.1
 )
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"strings"
+	"swarm/pkg"
+)
+
+// adminKeys handles destructive actions of the streams page lists: `x` deletes selected messages
+// (or the current one) of messages list, `t` trims and `D` deletes a stream selected on streams list.
+func (t *Terminal) adminKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || t.monitor == nil {
+		return event
+	}
+
+	switch {
+	case event.Rune() == 'x' && t.messages.HasFocus() && t.activeStream != nil:
+		t.confirmDeleteMessages(t.activeStream.Name, t.selectedMessages())
+		return nil
+	case event.Rune() == 't' && t.streams.HasFocus():
+		if stream := t.selectedStream(); stream != nil {
+			t.showTrim(stream.Name)
+		}
+		return nil
+	case event.Rune() == 'D' && t.streams.HasFocus():
+		if stream := t.selectedStream(); stream != nil {
+			t.confirmTyped(fmt.Sprintf("Delete %s stream with all its messages and consumer groups (DEL)?", stream.Name), stream.Name, "Delete stream", func() {
+				go t.runAdmin("DEL "+stream.Name, func() (string, error) {
+					return "", t.monitor.DeleteStream(stream.Name)
+				})
+			})
+		}
+		return nil
+	}
+
+	return event
+}
+
+// groupAdminKeys handles destructive actions of the groups list: `s` sets last delivered ID of a group,
+// `X` destroys a group and `k` deletes a consumer of a group.
+func (t *Terminal) groupAdminKeys(event *tcell.EventKey) *tcell.EventKey {
+	key := t.groups.GetCurrentItem()
+	if event.Key() != tcell.KeyRune || t.monitor == nil || key >= len(t.groupRows) {
+		return event
+	}
+
+	row := t.groupRows[key]
+	switch event.Rune() {
+	case 's':
+		t.prompt(fmt.Sprintf("Set last delivered ID of %s group", row.group), "ID ($ for last)", "Set ID", []string{"$", "0"}, func(id string) {
+			if id == "" {
+				return
+			}
+
+			t.confirmTyped(fmt.Sprintf("Set last delivered ID of %s group on %s stream to %s (XGROUP SETID)?", row.group, row.stream, id), row.group, "Set ID", func() {
+				go t.runAdmin(fmt.Sprintf("XGROUP SETID %s %s %s", row.stream, row.group, id), func() (string, error) {
+					return "", t.monitor.SetGroupID(row.stream, row.group, id)
+				})
+			})
+		})
+		return nil
+	case 'X':
+		t.confirmTyped(fmt.Sprintf("Destroy %s group of %s stream with all its consumers and pending entries (XGROUP DESTROY)?", row.group, row.stream), row.group, "Destroy group", func() {
+			go t.runAdmin(fmt.Sprintf("XGROUP DESTROY %s %s", row.stream, row.group), func() (string, error) {
+				return "", t.monitor.DestroyGroup(row.stream, row.group)
+			})
+		})
+		return nil
+	case 'k':
+		t.prompt(fmt.Sprintf("Delete consumer of %s group", row.group), "Consumer", "Delete", t.consumerNames(t.monitor, row), func(consumer string) {
+			if consumer == "" {
+				return
+			}
+
+			t.confirmTyped(fmt.Sprintf("Delete %s consumer of %s group on %s stream, dropping its pending entries (XGROUP DELCONSUMER)?", consumer, row.group, row.stream), consumer, "Delete consumer", func() {
+				go t.runAdmin(fmt.Sprintf("XGROUP DELCONSUMER %s %s %s", row.stream, row.group, consumer), func() (string, error) {
+					n, err := t.monitor.DeleteConsumer(row.stream, row.group, consumer)
+					return fmt.Sprintf("%d pending entries dropped", n), err
+				})
+			})
+		})
+		return nil
+	}
+
+	return event
+}
+
+// confirmDeleteMessages of a stream with XDEL, typing the stream name.
+func (t *Terminal) confirmDeleteMessages(stream string, messages []pkg.StreamMessage) {
+	if len(messages) == 0 {
+		return
+	}
+
+	var ids []pkg.StreamID
+	var names []string
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+		names = append(names, m.ID.String())
+	}
+
+	if len(names) > 5 {
+		names = append(names[:5], "...")
+	}
+
+	text := fmt.Sprintf("Delete %d message(s) of %s stream (XDEL %s)?", len(ids), stream, strings.Join(names, " "))
+	t.confirmTyped(text, stream, "Delete messages", func() {
+		t.selected = nil
+		if t.activeStream != nil {
+			t.showMessages(t.activeStream)
+		}
+		go t.runAdmin(fmt.Sprintf("XDEL %s of %d message(s)", stream, len(ids)), func() (string, error) {
+			n, err := t.monitor.DeleteMessages(stream, ids...)
+			return fmt.Sprintf("%d deleted", n), err
+		})
+	})
+}
+
+// showTrim dialog of a stream, trimming it by MAXLEN or MINID after typed confirmation.
+func (t *Terminal) showTrim(stream string) {
+	form := tview.NewForm()
+	form.AddDropDown("Strategy", []string{pkg.TrimMaxLen, pkg.TrimMinID}, 0, nil)
+	form.AddInputField("Threshold", "", 30, nil, nil)
+	form.AddButton("Trim", func() {
+		_, strategy := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		threshold := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		if threshold == "" {
+			return
+		}
+
+		t.closeDialog()
+		t.confirmTyped(fmt.Sprintf("Trim %s stream (XTRIM %s %s)?", stream, strategy, threshold), stream, "Trim stream", func() {
+			go t.runAdmin(fmt.Sprintf("XTRIM %s %s %s", stream, strategy, threshold), func() (string, error) {
+				n, err := t.monitor.Trim(stream, strategy, threshold)
+				return fmt.Sprintf("%d deleted", n), err
+			})
+		})
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Trim %s stream", stream)).SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	t.showDialog(center(form, 60, 9))
+}
+
+// runAdmin action described by its command, reporting its result on the status bar.
+func (t *Terminal) runAdmin(command string, action func() (string, error)) {
+	result, err := action()
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]%s failed: %v", command, err))
+		return
+	}
+
+	if result == "" {
+		result = "OK"
+	}
+
+	t.setStatus(fmt.Sprintf("%s: %s", command, result))
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"sort"
+	"strconv"
+	"strings"
+	"swarm/pkg"
+	"time"
+)
+
+// Message templates of compose form.
+const (
+	templateNone     = "None"
+	templateStreamer = "Laravel Streamer"
+)
+
+// composeRows is the index of the first field row in compose form, following stream, ID, MAXLEN and template items.
+const composeRows = 4
+
+// composeKeys handles `n` key of the streams page lists, opening compose form for a selected stream.
+func (t *Terminal) composeKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || event.Rune() != 'n' || t.monitor == nil {
+		return event
+	}
+
+	if t.monitor.ReadOnly {
+		go t.setStatus(fmt.Sprintf("[red]%s", pkg.ErrReadOnly))
+		return nil
+	}
+
+	stream := ""
+	if s := t.selectedStream(); s != nil {
+		stream = s.Name
+	}
+
+	t.showCompose(stream)
+
+	return nil
+}
+
+// selectedStream is the stream under cursor of the streams list when it has focus, otherwise the active stream.
+func (t *Terminal) selectedStream() *pkg.Stream {
+	if t.streams.HasFocus() && t.streams.GetItemCount() > 0 {
+		main, _ := t.streams.GetItemText(t.streams.GetCurrentItem())
+		return t.monitor.Streams.Find(itemName(main))
+	}
+
+	return t.activeStream
+}
+
+// showCompose form of a message published to an existing or a new stream.
+// Field rows can be added and removed, Laravel Streamer template replaces them with event name, domain and data.
+func (t *Terminal) showCompose(stream string) {
+	form := &composeForm{Form: tview.NewForm()}
+	form.AddInputField("Stream", stream, 40, nil, nil)
+	form.GetFormItem(0).(*tview.InputField).SetAutocompleteFunc(t.streamNames)
+	form.AddInputField("ID", "", 40, nil, nil)
+	form.GetFormItem(1).(*tview.InputField).SetPlaceholder("* (auto)")
+	form.AddInputField("MAXLEN ~", "", 12, tview.InputFieldInteger, nil)
+	template := templateNone
+	form.AddDropDown("Template", []string{templateNone, templateStreamer}, 0, func(option string, index int) {
+		if option == template {
+			return
+		}
+
+		template = option
+		if option == templateStreamer {
+			form.setRows([]pkg.Field{{Name: "name"}, {Name: "domain"}, {Name: "data", Value: "{}"}})
+		} else {
+			form.setRows([]pkg.Field{{}})
+		}
+	})
+	form.setRows([]pkg.Field{{}})
+
+	form.AddButton("Publish", func() {
+		p, err := composedPublication(form, template, time.Now())
+		if err != nil {
+			go t.setStatus(fmt.Sprintf("[red]%s", err))
+			return
+		}
+
+		t.closeDialog()
+		go t.publish(p)
+	})
+	form.AddButton("Add field", func() {
+		form.setRows(append(form.fields(), pkg.Field{}))
+	})
+	form.AddButton("Remove field", func() {
+		if fields := form.fields(); len(fields) > 1 {
+			form.setRows(fields[:len(fields)-1])
+		}
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBorder(true).SetTitle("Publish message").SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	t.showDialog(center(form, 80, 30))
+}
+
+// streamNames of known streams starting with given text, for autocomplete.
+func (t *Terminal) streamNames(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	var names []string
+	for name := range t.monitor.Streams.All() {
+		if strings.HasPrefix(name, text) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// composeForm is a form of composed message with a changing number of field rows.
+type composeForm struct {
+	*tview.Form
+	rows int
+}
+
+// setRows of composed message fields, replacing current ones with name and value inputs of given fields.
+func (c *composeForm) setRows(fields []pkg.Field) {
+	for ; c.rows > 0; c.rows-- {
+		c.RemoveFormItem(composeRows).RemoveFormItem(composeRows)
+	}
+
+	for i, f := range fields {
+		c.AddInputField(fmt.Sprintf("Field %d", i+1), f.Name, 40, nil, nil)
+		c.AddInputField(fmt.Sprintf("Value %d", i+1), f.Value, 60, nil, nil)
+	}
+	c.rows = len(fields)
+}
+
+// fields entered in compose form rows.
+func (c *composeForm) fields() []pkg.Field {
+	var fields []pkg.Field
+	for i := 0; i < c.rows; i++ {
+		fields = append(fields, pkg.Field{
+			Name:  strings.TrimSpace(c.GetFormItem(composeRows + i*2).(*tview.InputField).GetText()),
+			Value: c.GetFormItem(composeRows + i*2 + 1).(*tview.InputField).GetText(),
+		})
+	}
+
+	return fields
+}
+
+// composedPublication of compose form. With Streamer template, name, domain and data rows
+// are wrapped in Streamer envelope created at given time and the other rows follow it.
+func composedPublication(form *composeForm, template string, now time.Time) (pkg.Publication, error) {
+	p := pkg.Publication{
+		Stream: strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+		Fields: form.fields(),
+	}
+
+	var err error
+	if id := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText()); id != "" && id != "*" {
+		if p.ID, err = pkg.ParseStreamID(id); err != nil {
+			return p, err
+		}
+	}
+
+	if maxLen := form.GetFormItem(2).(*tview.InputField).GetText(); maxLen != "" {
+		if p.MaxLen, err = strconv.ParseInt(maxLen, 10, 64); err != nil {
+			return p, fmt.Errorf("invalid MAXLEN %q", maxLen)
+		}
+	}
+
+	if template == templateStreamer {
+		envelope := make(map[string]string)
+		var other []pkg.Field
+		for _, f := range p.Fields {
+			switch f.Name {
+			case "name", "domain", "data":
+				envelope[f.Name] = f.Value
+			default:
+				other = append(other, f)
+			}
+		}
+
+		fields, err := pkg.StreamerEnvelope(envelope["name"], envelope["domain"], envelope["data"], now)
+		if err != nil {
+			return p, err
+		}
+
+		p.Fields = append(fields, other...)
+	}
+
+	return p, p.Validate()
+}
+
+// publish message, reporting the result on the status bar.
+func (t *Terminal) publish(p pkg.Publication) {
+	id, err := t.monitor.Publish(p)
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Failed to publish message to %s stream: %v", p.Stream, err))
+		return
+	}
+
+	t.setStatus(fmt.Sprintf("Published message %s to %s stream", id, p.Stream))
+}
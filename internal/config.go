@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/go-redis/redis"
+	"swarm"
+	"swarm/pkg"
+)
+
+// NewRedisClient connected to Redis server from configuration.
+func NewRedisClient(config swarm.Configuration) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:        fmt.Sprintf("%s:%d", config.RedisHost, config.RedisPort),
+		Password:    config.RedisPassword,
+		DB:          0,
+		ReadTimeout: -1,
+	})
+}
+
+// NewDecoders of message fields values with protobuf types and rules from configuration.
+// Protobuf rules are skipped with a warning when no descriptor set is configured.
+func NewDecoders(config swarm.Configuration) (*pkg.Decoders, error) {
+	decoders, err := pkg.NewDecoders(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ProtobufDescriptors != "" {
+		if err := decoders.LoadProtobuf(config.ProtobufDescriptors); err != nil {
+			return nil, err
+		}
+	}
+
+	protobuf := config.Protobuf
+	if config.ProtobufDescriptors == "" && len(protobuf) > 0 {
+		pkg.LogWarning(fmt.Sprintf("%d protobuf rule(s) skipped, protobuf_descriptors is not set", len(protobuf)))
+		protobuf = nil
+	}
+
+	var rules []pkg.DecoderRule
+	for _, r := range protobuf {
+		rules = append(rules, pkg.DecoderRule{Stream: r.Stream, Field: r.Field, Decoders: []string{pkg.ProtobufPrefix + r.Message}})
+	}
+
+	for _, r := range config.Decoders {
+		rules = append(rules, pkg.DecoderRule{Stream: r.Stream, Field: r.Field, Decoders: r.Decoders})
+	}
+
+	for _, r := range rules {
+		if err := decoders.AddRule(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return decoders, nil
+}
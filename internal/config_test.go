@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"swarm"
+	"testing"
+)
+
+func TestNewDecoders_example(t *testing.T) {
+	config := swarm.ConfigFile("../config_example.json")
+	if len(config.Protobuf) == 0 || len(config.Decoders) == 0 {
+		t.Fatal("example configuration has no decoders rules")
+	}
+
+	if _, err := NewDecoders(config); err != nil {
+		t.Errorf("NewDecoders() of example configuration error = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/rivo/tview"
+	"swarm/pkg"
+	"time"
+)
+
+// dashboardPage is a name of the Dashboard page.
+const dashboardPage = "4"
+
+// dashboardTop is a number of streams and groups listed in dashboard rankings.
+const dashboardTop = 10
+
+// makeDashboardPage prepares the content of the Dashboard page
+// where it shows summary of all streams, their consumer groups and listeners
+func makeDashboardPage(t *Terminal) *tview.Flex {
+	t.dashboard = tview.NewTextView().SetDynamicColors(true)
+	t.dashboard.SetBorder(true).SetTitle("Dashboard").SetBackgroundColor(color)
+	t.dashboard.SetScrollable(true)
+
+	flex := tview.NewFlex().AddItem(t.dashboard, 0, 1, true)
+	flex.SetBackgroundColor(color)
+
+	return flex
+}
+
+// refreshDashboard every DashboardInterval while dashboard page is shown, until terminal is stopped.
+func (t *Terminal) refreshDashboard() {
+	ticker := time.NewTicker(t.DashboardInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.app.QueueUpdate(func() {
+				if t.page == dashboardPage {
+					go t.updateDashboard(t.listener)
+				}
+			})
+		}
+	}
+}
+
+// updateDashboard with current summary of streams and listeners.
+func (t *Terminal) updateDashboard(listener *pkg.Listener) {
+	summary, err := t.monitor.Summary(dashboardTop)
+	if err != nil {
+		pkg.LogWarning(err.Error())
+		t.setStatus(fmt.Sprintf("[red]Failed to refresh dashboard: %v", err))
+		return
+	}
+
+	var listeners []pkg.StreamListener
+	if listener != nil {
+		listeners = listener.Items()
+	}
+
+	content := dashboardContent(summary, listeners, time.Now())
+	t.app.QueueUpdateDraw(func() {
+		t.dashboard.Clear()
+		_, _ = fmt.Fprint(t.dashboard, content)
+	})
+}
+
+// dashboardContent describes streams summary and listeners states.
+func dashboardContent(summary pkg.Summary, listeners []pkg.StreamListener, now time.Time) string {
+	content := fmt.Sprintf("Streams: %d    Entries: %d    Memory: %s    [grey]refreshed at %s[white]\n\n",
+		summary.Streams, summary.Entries, formatBytes(summary.Memory), now.Format("15:04:05"))
+
+	content += "Top streams by rate (last minute)\n"
+	if len(summary.TopRates) == 0 {
+		content += "  [grey]no streams[white]\n"
+	}
+	for _, r := range summary.TopRates {
+		content += fmt.Sprintf("  %-10s %s\n", formatRate(r.Rate), tview.Escape(r.Stream))
+	}
+
+	content += "\nGroups with most pending entries\n"
+	if len(summary.TopPending) == 0 {
+		content += "  [grey]no consumer groups[white]\n"
+	}
+	for _, g := range summary.TopPending {
+		content += fmt.Sprintf("  %-10d %s: %s\n", g.Pending, tview.Escape(g.Stream), tview.Escape(g.Group))
+	}
+
+	counts := make(map[string]int)
+	for _, l := range listeners {
+		counts[l.State()]++
+	}
+
+	content += "\nListeners\n"
+	content += fmt.Sprintf("  [green]ok: %d[white]  [yellow]warning: %d[white]  [red]error: %d[white]  [grey]stopped: %d[white]\n",
+		counts[pkg.ListenerOK], counts[pkg.ListenerWarning], counts[pkg.ListenerError], counts[pkg.ListenerStopped])
+
+	return content
+}
+
+// formatBytes as a human readable size.
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
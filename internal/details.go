@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"fmt"
+	"swarm/pkg"
+	"time"
+)
+
+// streamDetails of a stream read from Redis.
+type streamDetails struct {
+	stream *pkg.Stream
+	info   *pkg.StreamInfo
+	ttl    time.Duration
+}
+
+// loadStreamDetails of a stream from Redis and show them in the header when it is still the active stream.
+func (t *Terminal) loadStreamDetails(stream *pkg.Stream) {
+	info, err := t.monitor.StreamInfo(stream.Name)
+	if err != nil && err != pkg.ErrNoStream {
+		pkg.LogWarning(err.Error())
+		return
+	}
+
+	var ttl time.Duration
+	if err == nil {
+		ttl, err = t.monitor.StreamTTL(stream.Name)
+		if err != nil && err != pkg.ErrNoStream {
+			pkg.LogWarning(err.Error())
+		}
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		if t.activeStream != stream {
+			return
+		}
+
+		t.details = &streamDetails{stream: stream, info: info, ttl: ttl}
+		t.showStreamHeader()
+	})
+}
+
+// detailsHeader describes XINFO STREAM data of a stream, next to the number of stored messages.
+func detailsHeader(details *streamDetails) string {
+	stored := details.stream.MessagesCount()
+	if details.info == nil {
+		return fmt.Sprintf("[grey]stream key does not exist, stored: %d[white]\n", stored)
+	}
+
+	info := details.info
+	ttl := "none"
+	if details.ttl > 0 {
+		ttl = details.ttl.Round(time.Second).String()
+	}
+
+	header := fmt.Sprintf("length: %d (stored: %d)  radix tree keys: %d nodes: %d  groups: %d  TTL: %s\n",
+		info.Length, stored, info.RadixTreeKeys, info.RadixTreeNodes, info.Groups, ttl)
+	header += fmt.Sprintf("last generated: %s  first entry: %s  last entry: %s\n",
+		info.LastGeneratedID, entryLabel(info.FirstEntryID), entryLabel(info.LastEntryID))
+
+	return header
+}
+
+// entryLabel formats ID of a stream entry with its local time, or "none" for zero ID.
+func entryLabel(id pkg.StreamID) string {
+	if id.IsZero() {
+		return "none"
+	}
+
+	return fmt.Sprintf("%s (%s)", id, id.Time().Local().Format("2006-01-02 15:04:05"))
+}
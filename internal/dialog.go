@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"strings"
+)
+
+// dialogPage is a name of the page shown over the current tab while a dialog is open.
+const dialogPage = "dialog"
+
+// showDialog over the current tab and focus it. Tabs cannot be switched until the dialog is closed.
+func (t *Terminal) showDialog(dialog tview.Primitive) {
+	t.dialogFocus = t.app.GetFocus()
+	t.pages.AddPage(dialogPage, dialog, true, true)
+	t.app.SetFocus(dialog)
+}
+
+// closeDialog and get focus back to where it was before the dialog was shown.
+func (t *Terminal) closeDialog() {
+	t.pages.RemovePage(dialogPage)
+	if t.dialogFocus != nil {
+		t.app.SetFocus(t.dialogFocus)
+	}
+}
+
+// confirm action with a modal dialog. Confirmed func is invoked only when user chooses the action button.
+func (t *Terminal) confirm(text, action string, confirmed func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{action, "Cancel"}).
+		SetDoneFunc(func(index int, label string) {
+			t.closeDialog()
+			if label == action {
+				confirmed()
+			}
+		})
+	modal.SetBackgroundColor(color)
+
+	t.showDialog(modal)
+}
+
+// prompt for a single value with a form dialog, suggesting values starting with typed text.
+// Entered func is invoked with trimmed value when user chooses the action button.
+func (t *Terminal) prompt(title, label, action string, suggestions []string, entered func(value string)) {
+	form := tview.NewForm()
+	form.AddInputField(label, "", 40, nil, nil)
+	input := form.GetFormItem(0).(*tview.InputField)
+	input.SetAutocompleteFunc(func(text string) []string {
+		var entries []string
+		for _, s := range suggestions {
+			if strings.HasPrefix(s, text) {
+				entries = append(entries, s)
+			}
+		}
+
+		return entries
+	})
+
+	form.AddButton(action, func() {
+		t.closeDialog()
+		entered(strings.TrimSpace(input.GetText()))
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBorder(true).SetTitle(title).SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	t.showDialog(center(form, 60, 7))
+}
+
+// center primitive on the screen with given size.
+func center(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// confirmTyped destructive action with a dialog where user has to type expected text, like a stream name.
+// Confirmed func is invoked only when typed text matches and user chooses the action button.
+func (t *Terminal) confirmTyped(text, expected, action string, confirmed func()) {
+	info := tview.NewTextView().
+		SetWrap(true).
+		SetText(fmt.Sprintf("%s\n\nThis cannot be undone. Type %q to confirm.", text, expected))
+	info.SetBackgroundColor(color)
+
+	form := tview.NewForm()
+	form.AddInputField("Confirm", "", 40, nil, nil)
+	input := form.GetFormItem(0).(*tview.InputField)
+	input.SetChangedFunc(func(text string) {
+		input.SetFieldTextColor(color)
+	})
+	form.AddButton(action, func() {
+		if input.GetText() != expected {
+			input.SetFieldTextColor(tcell.ColorRed)
+			return
+		}
+
+		t.closeDialog()
+		confirmed()
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(info, 0, 1, false).
+		AddItem(form, 5, 1, true)
+	flex.SetBorder(true).SetTitle(action).SetBackgroundColor(color)
+
+	t.showDialog(center(flex, 70, 14))
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"os"
+	"strings"
+	"swarm/pkg"
+)
+
+// exportExtensions of exported files by export format.
+var exportExtensions = map[string]string{
+	pkg.ExportNDJSON: ".ndjson",
+	pkg.ExportCSV:    ".csv",
+	pkg.ExportXADD:   ".redis",
+}
+
+// exportKeys handles `e` key of the streams page lists, opening export dialog of a selected stream.
+func (t *Terminal) exportKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || event.Rune() != 'e' || t.monitor == nil {
+		return event
+	}
+
+	if stream := t.selectedStream(); stream != nil {
+		t.showExport(stream.Name)
+	}
+
+	return nil
+}
+
+// showExport dialog of a stream messages, exported to a file in the working directory by default.
+// Messages can be limited to a range of IDs or times and to the ones matching current messages filter.
+func (t *Terminal) showExport(stream string) {
+	format := pkg.ExportNDJSON
+	form := tview.NewForm()
+	form.AddInputField("File", stream+exportExtensions[format], 40, nil, nil)
+	file := form.GetFormItem(0).(*tview.InputField)
+	form.AddDropDown("Format", pkg.ExportFormats, 0, func(option string, index int) {
+		if name := file.GetText(); strings.HasSuffix(name, exportExtensions[format]) {
+			file.SetText(strings.TrimSuffix(name, exportExtensions[format]) + exportExtensions[option])
+		}
+		format = option
+	})
+	form.AddInputField("From (ID or time)", "", 40, nil, nil)
+	form.AddInputField("To (ID or time)", "", 40, nil, nil)
+	form.AddCheckbox("Only filtered messages", t.filter != nil, nil)
+
+	form.AddButton("Export", func() {
+		o := pkg.ExportOptions{Stream: stream, Format: format}
+		var err error
+		if o.From, err = pkg.ParseRangeBound(form.GetFormItem(2).(*tview.InputField).GetText(), false); err != nil {
+			go t.setStatus(fmt.Sprintf("[red]%s", err))
+			return
+		}
+		if o.To, err = pkg.ParseRangeBound(form.GetFormItem(3).(*tview.InputField).GetText(), true); err != nil {
+			go t.setStatus(fmt.Sprintf("[red]%s", err))
+			return
+		}
+		if form.GetFormItem(4).(*tview.Checkbox).IsChecked() {
+			o.Filter = t.filter
+		}
+
+		t.closeDialog()
+		go t.export(o, strings.TrimSpace(file.GetText()))
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Export %s stream", stream)).SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	t.showDialog(center(form, 70, 15))
+}
+
+// export messages to a file, reporting the result on the status bar.
+func (t *Terminal) export(o pkg.ExportOptions, path string) {
+	t.setStatus(fmt.Sprintf("Exporting %s stream to %s...", o.Stream, path))
+	f, err := os.Create(path)
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Failed to export %s stream: %v", o.Stream, err))
+		return
+	}
+
+	n, err := t.monitor.Export(f, o)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Failed to export %s stream after %d message(s): %v", o.Stream, n, err))
+		return
+	}
+
+	t.setStatus(fmt.Sprintf("Exported %d message(s) of %s stream to %s", n, o.Stream, path))
+}
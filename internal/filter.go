@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"sort"
+	"strings"
+	"swarm/pkg"
+)
+
+// searchLimit is the highest number of messages shown in search results.
+const searchLimit = 1000
+
+// makeFilterInput creates the `/` prompt below messages list, which narrows the list while filter is typed.
+// Enter keeps the filter and goes back to messages, Escape clears it.
+func makeFilterInput(t *Terminal) *tview.InputField {
+	input := tview.NewInputField().
+		SetLabel("/ ").
+		SetPlaceholder("filter messages, e.g. name=user.created $.data.id=5").
+		SetFieldBackgroundColor(color).
+		SetPlaceholderTextColor(tcell.ColorGrey)
+	input.SetBackgroundColor(color)
+
+	input.SetChangedFunc(func(text string) {
+		t.applyFilter(text)
+	})
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			input.SetText("")
+		}
+
+		t.app.SetFocus(t.messages)
+	})
+
+	return input
+}
+
+// filterKeys handles filter shortcuts of the streams page lists:
+// `/` focuses the messages filter and Ctrl+F searches messages of all streams.
+func (t *Terminal) filterKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyRune && event.Rune() == '/':
+		t.app.SetFocus(t.filterInput)
+		return nil
+	case event.Key() == tcell.KeyCtrlF && t.monitor != nil:
+		t.prompt("Search all streams", "Filter", "Search", nil, func(query string) {
+			go t.search(query)
+		})
+		return nil
+	}
+
+	return event
+}
+
+// applyFilter typed into the filter prompt to messages list of the active stream.
+// Invalid filter is marked red and keeps the list as it was.
+func (t *Terminal) applyFilter(query string) {
+	if strings.TrimSpace(query) == "" {
+		t.filter = nil
+	} else {
+		f, err := pkg.ParseFilter(query, t.Decoders)
+		if err != nil {
+			t.filterInput.SetFieldTextColor(tcell.ColorRed)
+			return
+		}
+
+		t.filter = f
+	}
+
+	t.filterInput.SetFieldTextColor(tcell.ColorWhite)
+	if t.activeStream != nil {
+		t.showMessages(t.activeStream)
+	}
+}
+
+// matchFilter checks if message of a stream is shown with the current filter.
+func (t *Terminal) matchFilter(stream *pkg.Stream, message pkg.StreamMessage) bool {
+	return t.filter == nil || t.filter.Match(stream.Name, message)
+}
+
+// search messages of all streams and show results in a dialog.
+func (t *Terminal) search(query string) {
+	f, err := pkg.ParseFilter(query, t.Decoders)
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]%s", err))
+		return
+	}
+
+	results := t.monitor.Search(f)
+	t.app.QueueUpdateDraw(func() {
+		t.showSearchResults(query, results)
+	})
+}
+
+// showSearchResults in a dialog, selecting a result jumps to its stream and message.
+func (t *Terminal) showSearchResults(query string, results []pkg.SearchResult) {
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true).SetBackgroundColor(color)
+	list.SetSelectedBackgroundColor(tcell.ColorWhite)
+	list.SetSelectedTextColor(color)
+	list.SetSecondaryTextColor(tcell.ColorWhite)
+	list.SetDoneFunc(t.closeDialog)
+
+	title := fmt.Sprintf("%d messages matching %s", len(results), query)
+	if len(results) > searchLimit {
+		title = fmt.Sprintf("%d of %d messages matching %s", searchLimit, len(results), query)
+		results = results[:searchLimit]
+	}
+	list.SetTitle(title)
+
+	for _, r := range results {
+		list.AddItem(fmt.Sprintf("%s  %s", r.Stream, messageItem(r.Message)), "  "+preview(r.Message), 0, nil)
+	}
+
+	list.SetSelectedFunc(func(key int, main, secondary string, short rune) {
+		t.closeDialog()
+		t.jumpToMessage(results[key].Stream, results[key].Message.ID)
+	})
+
+	t.showDialog(center(list, 100, 30))
+}
+
+// jumpToMessage shows message of a stream on the streams page, clearing messages filter.
+func (t *Terminal) jumpToMessage(name string, id pkg.StreamID) {
+	stream := t.monitor.Streams.Find(name)
+	key := t.FindStreamKey(name)
+	if stream == nil || key < 0 {
+		return
+	}
+
+	t.switchPage("1")
+	t.filterInput.SetText("")
+	t.streams.SetCurrentItem(key)
+	t.showMessages(stream)
+	for i := 0; i < t.messages.GetItemCount(); i++ {
+		main, _ := t.messages.GetItemText(i)
+		if current, err := messageID(main); err == nil && current == id {
+			t.messages.SetCurrentItem(i)
+			break
+		}
+	}
+
+	t.app.SetFocus(t.messages)
+}
+
+// preview of message fields in a single line, sorted by field name.
+func preview(m pkg.StreamMessage) string {
+	var fields []string
+	for k, v := range m.Content {
+		fields = append(fields, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(fields)
+
+	text := []rune(strings.Join(strings.Fields(strings.Join(fields, " ")), " "))
+	if len(text) > 90 {
+		text = append(text[:90], '…')
+	}
+
+	return tview.Escape(string(text))
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"sort"
+	"swarm/pkg"
+	"time"
+)
+
+// groupRow identifies consumer group shown in a row of groups list.
+type groupRow struct {
+	stream string
+	group  string
+}
+
+// makeGroupsPage prepares the content of the Groups page
+// where it shows consumer groups of all streams, consumers of a selected group
+// and its pending entries
+func makeGroupsPage(t *Terminal) *tview.Flex {
+	t.groups = tview.NewList().ShowSecondaryText(true)
+	t.groups.SetBorder(true).SetBackgroundColor(color)
+	t.groups.SetSelectedBackgroundColor(tcell.ColorWhite)
+	t.groups.SetSelectedTextColor(color)
+	t.groups.SetSecondaryTextColor(tcell.ColorWhite)
+	t.groups.SetTitle("Consumer groups list (s: set ID, X: destroy, k: delete consumer)")
+
+	t.consumers = tview.NewTextView().SetDynamicColors(true)
+	t.consumers.SetBorder(true).SetTitle("Group consumers").SetBackgroundColor(color)
+	t.consumers.SetScrollable(true)
+	t.pending, t.pendingContent = makePendingList(t)
+
+	flex := tview.NewFlex().
+		AddItem(t.groups, 0, 1, true).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(t.consumers, 0, 1, false).
+			AddItem(t.pending, 0, 1, false).
+			AddItem(t.pendingContent, 0, 1, false), 0, 2, false)
+	flex.SetBackgroundColor(color)
+
+	return flex
+}
+
+// bindGroups binds groups page updates to monitor events.
+func (t *Terminal) bindGroups(monitor *pkg.Monitor) {
+	monitor.OnGroupsUpdated(func(stream *pkg.Stream) {
+		t.app.QueueUpdateDraw(func() {
+			t.showGroups(stream.Name, stream.Groups(), monitor)
+		})
+	})
+
+	monitor.OnStreamRemoved(func(stream *pkg.Stream) {
+		t.app.QueueUpdateDraw(func() {
+			t.showGroups(stream.Name, nil, monitor)
+		})
+	})
+
+	monitor.OnStreamForgotten(func(stream *pkg.Stream) {
+		t.app.QueueUpdateDraw(func() {
+			t.showGroups(stream.Name, nil, monitor)
+		})
+	})
+
+	monitor.OnStreamRenamed(func(stream *pkg.Stream, oldName string) {
+		t.app.QueueUpdateDraw(func() {
+			t.showGroups(oldName, nil, monitor)
+			t.showGroups(stream.Name, stream.Groups(), monitor)
+		})
+	})
+
+	t.groups.SetChangedFunc(func(key int, main, secondary string, short rune) {
+		t.showConsumers(monitor, key)
+	})
+
+	t.groups.SetSelectedFunc(func(key int, main, secondary string, short rune) {
+		if key >= len(t.groupRows) {
+			return
+		}
+
+		go t.loadPending(monitor, t.groupRows[key])
+		t.app.SetFocus(t.pending)
+	})
+
+	t.groups.SetInputCapture(t.groupAdminKeys)
+
+	t.bindPending(monitor)
+}
+
+// showGroups replaces groups list rows of a stream with given groups,
+// keeping rows ordered by stream name and the current selection.
+func (t *Terminal) showGroups(stream string, groups []pkg.ConsumerGroup, monitor *pkg.Monitor) {
+	var selected *groupRow
+	if current := t.groups.GetCurrentItem(); current < len(t.groupRows) {
+		row := t.groupRows[current]
+		selected = &row
+	}
+
+	for i := len(t.groupRows) - 1; i >= 0; i-- {
+		if t.groupRows[i].stream == stream {
+			t.groupRows = append(t.groupRows[:i], t.groupRows[i+1:]...)
+			t.groups.RemoveItem(i)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	key := sort.Search(len(t.groupRows), func(i int) bool {
+		return t.groupRows[i].stream > stream
+	})
+	for _, g := range groups {
+		row := groupRow{stream: stream, group: g.Name}
+		t.groupRows = append(t.groupRows, groupRow{})
+		copy(t.groupRows[key+1:], t.groupRows[key:])
+		t.groupRows[key] = row
+
+		main, secondary := groupItem(stream, g)
+		t.groups.InsertItem(key, main, secondary, 0, nil)
+		key++
+	}
+
+	if selected == nil {
+		return
+	}
+
+	for i, row := range t.groupRows {
+		if row == *selected {
+			t.groups.SetCurrentItem(i)
+			break
+		}
+	}
+
+	if selected.stream == stream {
+		t.showConsumers(monitor, t.groups.GetCurrentItem())
+	}
+}
+
+// showConsumers of a group from given groups list row.
+func (t *Terminal) showConsumers(monitor *pkg.Monitor, key int) {
+	t.consumers.Clear()
+	if key < 0 || key >= len(t.groupRows) {
+		return
+	}
+
+	row := t.groupRows[key]
+	stream := monitor.Streams.Find(row.stream)
+	if stream == nil {
+		return
+	}
+
+	for _, g := range stream.Groups() {
+		if g.Name == row.group {
+			_, _ = fmt.Fprint(t.consumers, groupContent(row.stream, g))
+			return
+		}
+	}
+}
+
+// groupItem returns main and secondary text of a group row on the groups list.
+func groupItem(stream string, group pkg.ConsumerGroup) (string, string) {
+	return fmt.Sprintf("%s: %s", stream, group.Name),
+		fmt.Sprintf("- pending: %d, lag: %s, consumers: %d", group.Pending, groupLag(group), len(group.Consumers))
+}
+
+// groupContent describes consumer group with all its consumers.
+func groupContent(stream string, group pkg.ConsumerGroup) string {
+	content := fmt.Sprintf("Stream: %s\r\n", stream)
+	content += fmt.Sprintf("Group: %s\r\n", group.Name)
+	content += fmt.Sprintf("Last delivered ID: %s\r\n", group.LastDeliveredID)
+	content += fmt.Sprintf("Pending: %d\r\n", group.Pending)
+	content += fmt.Sprintf("Lag: %s\r\n\r\n", groupLag(group))
+
+	if len(group.Consumers) == 0 {
+		return content + "[grey]No consumers[white]\r\n"
+	}
+
+	content += "Consumers:\r\n"
+	for _, c := range group.Consumers {
+		content += fmt.Sprintf("%s - pending: %d, idle: %s\r\n", c.Name, c.Pending, c.Idle.Round(time.Second))
+	}
+
+	return content
+}
+
+// groupLag formats group lag, which is not available in older Redis versions.
+func groupLag(group pkg.ConsumerGroup) string {
+	if group.Lag < 0 {
+		return "n/a"
+	}
+
+	return fmt.Sprint(group.Lag)
+}
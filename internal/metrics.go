@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/rivo/tview"
+	"strings"
+	"swarm/pkg"
+	"time"
+)
+
+// sparks are characters of sparkline bars, from the lowest to the highest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// makeStreamHeader creates the view of active stream details shown above message content.
+func makeStreamHeader() *tview.TextView {
+	header := tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+	header.SetBorder(true).SetTitle("Stream details").SetBackgroundColor(color)
+
+	return header
+}
+
+// refreshMetrics of streams list rows and active stream header every second, until terminal is stopped.
+func (t *Terminal) refreshMetrics() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.app.QueueUpdateDraw(func() {
+				for key := 0; key < t.streams.GetItemCount(); key++ {
+					main, _ := t.streams.GetItemText(key)
+					if s := t.monitor.Streams.Find(itemName(main)); s != nil {
+						main, secondary := streamItem(s)
+						t.streams.SetItemText(key, main, secondary)
+					}
+				}
+
+				t.showStreamHeader()
+				if t.activeStream != nil {
+					go t.loadStreamDetails(t.activeStream)
+				}
+			})
+		}
+	}
+}
+
+// showStreamHeader with details and metrics of the active stream.
+func (t *Terminal) showStreamHeader() {
+	t.streamHeader.Clear()
+	if t.activeStream == nil {
+		return
+	}
+
+	_, _ = fmt.Fprint(t.streamHeader, streamHeader(t.activeStream, time.Now()))
+	if t.details != nil && t.details.stream == t.activeStream {
+		_, _ = fmt.Fprint(t.streamHeader, "\n"+detailsHeader(t.details))
+	}
+}
+
+// streamHeader describes stream throughput and lag of its consumer groups.
+func streamHeader(stream *pkg.Stream, now time.Time) string {
+	metrics := stream.Metrics(now)
+	header := fmt.Sprintf("%s  rate 1m: %s  5m: %s  15m: %s  last message: %s\n",
+		tview.Escape(stream.Name), formatRate(metrics.Rate1m), formatRate(metrics.Rate5m), formatRate(metrics.Rate15m), since(metrics.LastMessage, now))
+	header += fmt.Sprintf("last 15m: %s\n", sparkline(metrics.PerMinute))
+
+	groups := stream.Groups()
+	if len(groups) == 0 {
+		return header + "[grey]no consumer groups"
+	}
+
+	var lags []string
+	for _, g := range groups {
+		lags = append(lags, fmt.Sprintf("%s: %s", tview.Escape(g.Name), groupLag(g)))
+	}
+
+	return header + "groups lag: " + strings.Join(lags, ", ")
+}
+
+// formatRate of messages per second.
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%.2f/s", rate)
+}
+
+// since formats time passed from given time, or "never" for zero time.
+func since(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	d := now.Sub(t)
+	if d < 0 {
+		d = 0
+	}
+
+	return fmt.Sprintf("%s ago", d.Round(time.Second))
+}
+
+// sparkline of given values, scaled to the highest one.
+func sparkline(values []int64) string {
+	var max int64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	var line strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 {
+			i = int(v * int64(len(sparks)-1) / max)
+		}
+		line.WriteRune(sparks[i])
+	}
+
+	return line.String()
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"swarm/pkg"
+	"time"
+)
+
+// pendingLimit is the highest number of pending entries shown for a group.
+const pendingLimit = 1000
+
+// makePendingList creates the list of pending entries of a selected group
+// and the view of a selected entry message content.
+func makePendingList(t *Terminal) (*tview.List, *tview.TextView) {
+	pending := tview.NewList().ShowSecondaryText(false)
+	pending.SetBorder(true).SetBackgroundColor(color)
+	pending.SetSelectedBackgroundColor(tcell.ColorWhite)
+	pending.SetSelectedTextColor(color)
+	pending.SetTitle("Pending entries (a: ack, c: claim)")
+	pending.SetDoneFunc(func() {
+		t.app.SetFocus(t.groups)
+	})
+
+	content := tview.NewTextView()
+	content.SetBorder(true).SetTitle("Pending message content").SetBackgroundColor(color)
+
+	return pending, content
+}
+
+// bindPending binds pending entries list actions to monitor.
+func (t *Terminal) bindPending(monitor *pkg.Monitor) {
+	t.pending.SetChangedFunc(func(key int, main, secondary string, short rune) {
+		t.showPendingMessage(monitor, key)
+	})
+
+	t.pending.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		key := t.pending.GetCurrentItem()
+		if event.Key() != tcell.KeyRune || key >= len(t.pendingEntries) {
+			return event
+		}
+
+		row, entry := t.pendingGroup, t.pendingEntries[key]
+		switch event.Rune() {
+		case 'a':
+			t.confirm(fmt.Sprintf("Acknowledge message %s of group %s on %s stream?", entry.ID, row.group, row.stream), "Ack", func() {
+				go t.ackPending(monitor, row, entry)
+			})
+			return nil
+		case 'c':
+			t.prompt(fmt.Sprintf("Claim message %s", entry.ID), "Consumer", "Claim", t.consumerNames(monitor, row), func(consumer string) {
+				if consumer == "" {
+					return
+				}
+
+				t.confirm(fmt.Sprintf("Claim message %s of group %s on %s stream to %s consumer?", entry.ID, row.group, row.stream, consumer), "Claim", func() {
+					go t.claimPending(monitor, row, entry, consumer)
+				})
+			})
+			return nil
+		}
+
+		return event
+	})
+}
+
+// loadPending entries of a group and show them on the pending entries list.
+func (t *Terminal) loadPending(monitor *pkg.Monitor, row groupRow) {
+	entries, err := monitor.PendingEntries(row.stream, row.group, pendingLimit)
+	if err != nil {
+		pkg.LogWarning(err.Error())
+		t.setStatus(fmt.Sprintf("[red]Failed to read pending entries of %s group: %v", row.group, err))
+		return
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		current := 0
+		if t.pendingGroup == row {
+			current = t.pending.GetCurrentItem()
+		}
+
+		t.pendingGroup = row
+		t.pendingEntries = entries
+		t.pending.SetTitle(fmt.Sprintf("Pending entries of %s (a: ack, c: claim)", row.group))
+		t.pending.Clear()
+		t.pendingContent.Clear()
+		for _, e := range entries {
+			t.pending.AddItem(pendingItem(e), "", 0, nil)
+		}
+
+		t.pending.SetCurrentItem(current)
+		t.showPendingMessage(monitor, t.pending.GetCurrentItem())
+	})
+}
+
+// ackPending entry and reload pending entries of its group.
+func (t *Terminal) ackPending(monitor *pkg.Monitor, row groupRow, entry pkg.PendingEntry) {
+	n, err := monitor.Ack(row.stream, row.group, entry.ID)
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Failed to acknowledge message %s: %v", entry.ID, err))
+		return
+	}
+
+	t.setStatus(fmt.Sprintf("Acknowledged %d message(s) of %s group", n, row.group))
+	t.loadPending(monitor, row)
+}
+
+// claimPending entry to a consumer and reload pending entries of its group.
+func (t *Terminal) claimPending(monitor *pkg.Monitor, row groupRow, entry pkg.PendingEntry, consumer string) {
+	claimed, err := monitor.Claim(row.stream, row.group, consumer, entry.ID)
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Failed to claim message %s: %v", entry.ID, err))
+		return
+	}
+
+	t.setStatus(fmt.Sprintf("Claimed %d message(s) of %s group to %s consumer", len(claimed), row.group, consumer))
+	t.loadPending(monitor, row)
+}
+
+// showPendingMessage content of a pending entry, when the message is stored in stream.
+func (t *Terminal) showPendingMessage(monitor *pkg.Monitor, key int) {
+	t.pendingContent.Clear()
+	if key < 0 || key >= len(t.pendingEntries) {
+		return
+	}
+
+	stream := monitor.Streams.Find(t.pendingGroup.stream)
+	if stream == nil {
+		return
+	}
+
+	m, err := stream.GetMessage(t.pendingEntries[key].ID)
+	if err != nil {
+		_, _ = fmt.Fprint(t.pendingContent, "Message is not stored in memory.")
+		return
+	}
+
+	_, _ = fmt.Fprint(t.pendingContent, t.renderMessage(m, stream.Name))
+}
+
+// consumerNames of a group, as last collected from Redis.
+func (t *Terminal) consumerNames(monitor *pkg.Monitor, row groupRow) []string {
+	stream := monitor.Streams.Find(row.stream)
+	if stream == nil {
+		return nil
+	}
+
+	var names []string
+	for _, g := range stream.Groups() {
+		if g.Name != row.group {
+			continue
+		}
+
+		for _, c := range g.Consumers {
+			names = append(names, c.Name)
+		}
+	}
+
+	return names
+}
+
+// pendingItem returns main text of a pending entry row on the pending entries list.
+func pendingItem(entry pkg.PendingEntry) string {
+	return fmt.Sprintf("%s  [grey]%s, idle: %s, deliveries: %d", entry.ID, entry.Consumer, entry.Idle.Round(time.Second), entry.Deliveries)
+}
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"sort"
+	"strconv"
+	"strings"
+	"swarm/pkg"
+)
+
+// currentProfile is a name of the connection monitor is using, in republish connections list.
+const currentProfile = "current"
+
+// replayKeys handles messages list selection and republishing: space selects or unselects current message
+// and `r` republishes selected messages, or the current one when none is selected.
+func (t *Terminal) replayKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() != tcell.KeyRune || !t.messages.HasFocus() || t.activeStream == nil {
+		return event
+	}
+
+	switch event.Rune() {
+	case ' ':
+		t.toggleSelected()
+		return nil
+	case 'r':
+		if messages := t.selectedMessages(); len(messages) > 0 {
+			t.showReplay(t.activeStream.Name, messages)
+		}
+		return nil
+	}
+
+	return event
+}
+
+// messageRow returns main text of a message row on the messages list, marking selected messages.
+func (t *Terminal) messageRow(message pkg.StreamMessage) string {
+	if t.selected[message.ID] {
+		return messageItem(message) + "  [yellow]✔"
+	}
+
+	return messageItem(message)
+}
+
+// toggleSelected state of the current message of messages list.
+func (t *Terminal) toggleSelected() {
+	key := t.messages.GetCurrentItem()
+	if key >= t.messages.GetItemCount() {
+		return
+	}
+
+	main, secondary := t.messages.GetItemText(key)
+	id, err := messageID(main)
+	if err != nil {
+		return
+	}
+
+	m, err := t.activeStream.GetMessage(id)
+	if err != nil {
+		return
+	}
+
+	if t.selected == nil {
+		t.selected = make(map[pkg.StreamID]bool)
+	}
+
+	if t.selected[id] {
+		delete(t.selected, id)
+	} else {
+		t.selected[id] = true
+	}
+
+	t.messages.SetItemText(key, t.messageRow(*m), secondary)
+}
+
+// selectedMessages of the active stream from the oldest, or the current message when none is selected.
+func (t *Terminal) selectedMessages() []pkg.StreamMessage {
+	var ids []pkg.StreamID
+	for id := range t.selected {
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 && t.messages.GetItemCount() > 0 {
+		main, _ := t.messages.GetItemText(t.messages.GetCurrentItem())
+		if id, err := messageID(main); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Less(ids[j])
+	})
+
+	var messages []pkg.StreamMessage
+	for _, id := range ids {
+		if m, err := t.activeStream.GetMessage(id); err == nil {
+			messages = append(messages, *m)
+		}
+	}
+
+	return messages
+}
+
+// showReplay dialog of republishing messages to a stream of current or another connection profile,
+// with at most given number of messages per second. Preview shows commands without running them.
+func (t *Terminal) showReplay(stream string, messages []pkg.StreamMessage) {
+	profiles := []string{currentProfile}
+	for name := range t.Profiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles[1:])
+
+	form := tview.NewForm()
+	form.AddInputField("Target stream", stream, 40, nil, nil)
+	form.GetFormItem(0).(*tview.InputField).SetAutocompleteFunc(t.streamNames)
+	form.AddDropDown("Connection", profiles, 0, nil)
+	form.AddInputField("Rate (msg/s, 0: no limit)", "0", 10, tview.InputFieldFloat, nil)
+
+	options := func() (string, string, float64, error) {
+		target := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		_, profile := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		rate, err := strconv.ParseFloat(form.GetFormItem(2).(*tview.InputField).GetText(), 64)
+		if err != nil || rate < 0 {
+			return "", "", 0, fmt.Errorf("invalid rate %q", form.GetFormItem(2).(*tview.InputField).GetText())
+		}
+
+		if target == "" {
+			return "", "", 0, fmt.Errorf("target stream is required")
+		}
+
+		return target, profile, rate, nil
+	}
+
+	form.AddButton("Preview", func() {
+		target, profile, rate, err := options()
+		if err != nil {
+			go t.setStatus(fmt.Sprintf("[red]%s", err))
+			return
+		}
+
+		t.closeDialog()
+		t.showReplayPreview(target, profile, rate, messages)
+	})
+	form.AddButton("Republish", func() {
+		target, profile, rate, err := options()
+		if err != nil {
+			go t.setStatus(fmt.Sprintf("[red]%s", err))
+			return
+		}
+
+		t.closeDialog()
+		t.confirm(fmt.Sprintf("Republish %d message(s) to %s stream of %s connection?", len(messages), target, profile), "Republish", func() {
+			go t.replay(target, profile, rate, messages)
+		})
+	})
+	form.AddButton("Cancel", t.closeDialog)
+	form.SetCancelFunc(t.closeDialog)
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Republish %d message(s) of %s stream", len(messages), stream)).SetBackgroundColor(color)
+	form.SetFieldBackgroundColor(tcell.ColorWhite)
+	form.SetFieldTextColor(color)
+
+	t.showDialog(center(form, 70, 11))
+}
+
+// showReplayPreview of commands republishing messages, closed with escape.
+func (t *Terminal) showReplayPreview(target, profile string, rate float64, messages []pkg.StreamMessage) {
+	preview := tview.NewTextView()
+	preview.SetBorder(true).SetTitle("Dry run (escape to close)").SetBackgroundColor(color)
+	preview.SetScrollable(true)
+	preview.SetDoneFunc(func(key tcell.Key) {
+		t.closeDialog()
+	})
+
+	limit := "no rate limit"
+	if rate > 0 {
+		limit = fmt.Sprintf("at most %g message(s) per second", rate)
+	}
+	_, _ = fmt.Fprintf(preview, "# %d command(s) on %s connection, %s\n", len(messages), profile, limit)
+	for _, m := range messages {
+		_, _ = fmt.Fprintln(preview, pkg.Republication(target, m).Command())
+	}
+
+	t.showDialog(center(preview, 120, 30))
+}
+
+// replay messages to a stream of a connection profile, reporting progress on the status bar.
+func (t *Terminal) replay(target, profile string, rate float64, messages []pkg.StreamMessage) {
+	monitor := t.monitor
+	if profile != currentProfile {
+		monitor = t.Profiles[profile]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-t.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	n, err := monitor.Replay(ctx, target, messages, rate, func(n int) {
+		t.setStatus(fmt.Sprintf("Republishing to %s stream: %d/%d", target, n, len(messages)))
+	})
+	if err != nil {
+		t.setStatus(fmt.Sprintf("[red]Republished %d of %d message(s) to %s stream: %v", n, len(messages), target, err))
+		return
+	}
+
+	t.setStatus(fmt.Sprintf("Republished %d message(s) to %s stream of %s connection", n, target, profile))
+}
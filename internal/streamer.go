@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"swarm/pkg"
+)
+
+// renderMessage content for the message content view. Laravel Streamer events are shown
+// with their name, local created time, payload tree and local listeners, followed by decoded fields.
+func (t *Terminal) renderMessage(m *pkg.StreamMessage, stream string) string {
+	event, ok := m.StreamerEvent()
+	if !ok {
+		return m.DecodedContent(t.Decoders, stream)
+	}
+
+	content := fmt.Sprintf("Event: %s\r\n", event.Name)
+	if event.Domain != "" {
+		content += fmt.Sprintf("Domain: %s\r\n", event.Domain)
+	}
+	content += fmt.Sprintf("Created: %s\r\n", event.Created.Local().Format("2006-01-02 15:04:05"))
+	content += fmt.Sprintf("Handled by: %s\r\n\r\n", t.eventHandlers(event.Name))
+	content += "Payload:\r\n"
+	content += payloadTree(event.Data, "")
+	content += "\r\n" + m.DecodedContent(t.Decoders, stream)
+
+	return content
+}
+
+// eventHandlers describes local listeners of an event.
+func (t *Terminal) eventHandlers(event string) string {
+	if t.listener == nil {
+		return "unknown, listening is not available"
+	}
+
+	handlers, ok := t.listener.Handlers(event)
+	if !ok {
+		return "unknown, events listeners are not listed yet"
+	}
+
+	if len(handlers) == 0 {
+		return "no local listeners"
+	}
+
+	return strings.Join(handlers, ", ")
+}
+
+// payloadTree renders decoded JSON value as a tree, objects keys are sorted.
+func payloadTree(value interface{}, indent string) string {
+	type node struct {
+		key   string
+		value interface{}
+	}
+
+	var nodes []node
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, i := range v {
+			nodes = append(nodes, node{k, i})
+		}
+		sort.Slice(nodes, func(i, j int) bool {
+			return nodes[i].key < nodes[j].key
+		})
+	case []interface{}:
+		for k, i := range v {
+			nodes = append(nodes, node{fmt.Sprintf("[%d]", k), i})
+		}
+	default:
+		return indent + "└─ " + scalar(value) + "\r\n"
+	}
+
+	var tree string
+	for i, n := range nodes {
+		branch, next := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, next = "└─ ", "   "
+		}
+
+		switch n.value.(type) {
+		case map[string]interface{}, []interface{}:
+			tree += indent + branch + n.key + "\r\n"
+			tree += payloadTree(n.value, indent+next)
+		default:
+			tree += indent + branch + n.key + ": " + scalar(n.value) + "\r\n"
+		}
+	}
+
+	return tree
+}
+
+// scalar formats JSON value that is not an object or an array.
+func scalar(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(b)
+}
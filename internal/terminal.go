@@ -7,6 +7,7 @@ import (
 	"github.com/rivo/tview"
 	"strings"
 	"swarm/pkg"
+	"time"
 )
 
 var color = tcell.NewRGBColor(64, 69, 82)
@@ -17,41 +18,87 @@ type Terminal struct {
 	listeners          *tview.List
 	listenersOutput    *tview.TextView
 	messages           *tview.List
+	filterInput        *tview.InputField
+	filter             *pkg.Filter
+	selected           map[pkg.StreamID]bool
 	messageContent     *tview.TextView
-	activeStream       pkg.Stream
+	streamHeader       *tview.TextView
+	details            *streamDetails
+	monitor            *pkg.Monitor
+	groups             *tview.List
+	consumers          *tview.TextView
+	groupRows          []groupRow
+	pending            *tview.List
+	pendingContent     *tview.TextView
+	pendingGroup       groupRow
+	pendingEntries     []pkg.PendingEntry
+	tabs               *tview.TextView
+	pages              *tview.Pages
+	page               string
+	dashboard          *tview.TextView
+	watches            *tview.List
+	watchRows          []*pkg.Watch
+	matches            *tview.List
+	matchRows          []pkg.WatchMatch
+	listener           *pkg.Listener
+	dialogFocus        tview.Primitive
+	status             *tview.TextView
+	readOnly           bool
+	activeStream       *pkg.Stream
+	loadingHistory     bool
 	printDefaultOutput chan bool
+	done               chan struct{}
+	quitHandler        func()
 	Layout             *tview.Flex
+	// HistoryPage is a number of older messages loaded when scrolling past the top of messages list.
+	HistoryPage int64
+	// DashboardInterval is a delay between dashboard refreshes while it is shown.
+	DashboardInterval time.Duration
+	// Decoders of message fields values shown in message content.
+	Decoders *pkg.Decoders
+	// Profiles are monitors of other Redis connections messages can be republished to, by name.
+	Profiles map[string]*pkg.Monitor
 }
 
 func NewTerminal(app *tview.Application, withListener bool) *Terminal {
+	decoders, _ := pkg.NewDecoders(nil)
 	t := &Terminal{
 		app:                app,
 		printDefaultOutput: make(chan bool),
+		done:               make(chan struct{}),
+		HistoryPage:        100,
+		DashboardInterval:  time.Second * 5,
+		Decoders:           decoders,
+		page:               "1",
 	}
 
-	tabs := makeTabs()
+	t.tabs = makeTabs()
+	t.status = makeStatusBar()
 	pages := tview.NewPages()
+	t.pages = pages
 	pages.AddPage("1", makeStreamsPage(t), true, true)
 	pages.AddPage("2", makeListenersPage(t, withListener), true, false)
+	pages.AddPage("3", makeGroupsPage(t), true, false)
+	pages.AddPage(dashboardPage, makeDashboardPage(t), true, false)
+	pages.AddPage(watchesPage, makeWatchesPage(t), true, false)
 
 	layout := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(tabs, 1, 1, false).
-		AddItem(pages, 0, 1, true)
+		AddItem(t.tabs, 1, 1, false).
+		AddItem(pages, 0, 1, true).
+		AddItem(t.status, 1, 1, false)
 	layout.SetBackgroundColor(color)
 
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyRune {
-			if event.Rune() == 49 {
-				tabs.Highlight("1").ScrollToHighlight()
-				pages.SwitchToPage("1")
-			} else if event.Rune() == 50 {
-				tabs.Highlight("2").ScrollToHighlight()
-				pages.SwitchToPage("2")
-				if t.listeners != nil {
-					t.printDefaultOutput <- true
-				}
-			}
+		if event.Key() == tcell.KeyCtrlC && t.quitHandler != nil {
+			go t.quitHandler()
+			return nil
+		}
+
+		// digits typed into input fields must not switch tabs
+		_, typing := t.app.GetFocus().(*tview.InputField)
+		if event.Key() == tcell.KeyRune && !typing && !pages.HasPage(dialogPage) && pages.HasPage(string(event.Rune())) {
+			t.switchPage(string(event.Rune()))
 		}
 
 		return event
@@ -62,6 +109,34 @@ func NewTerminal(app *tview.Application, withListener bool) *Terminal {
 	return t
 }
 
+// switchPage to the tab of given name, highlighting it on the tabs bar.
+func (t *Terminal) switchPage(page string) {
+	t.tabs.Highlight(page).ScrollToHighlight()
+	t.pages.SwitchToPage(page)
+	t.page = page
+	if page == dashboardPage && t.monitor != nil {
+		go t.updateDashboard(t.listener)
+	}
+
+	if page == "2" && t.listeners != nil {
+		select {
+		case t.printDefaultOutput <- true:
+		case <-t.done:
+		}
+	}
+}
+
+// OnQuit assigns handler invoked in background when user quits with Ctrl+C,
+// instead of stopping the application right away. Handler is responsible for stopping it.
+func (t *Terminal) OnQuit(handler func()) {
+	t.quitHandler = handler
+}
+
+// Stop background work of the terminal.
+func (t *Terminal) Stop() {
+	close(t.done)
+}
+
 // makeTabs creates the information about how many tabs/pages are there
 // and what numbers are associated with them
 func makeTabs() *tview.TextView {
@@ -74,10 +149,24 @@ func makeTabs() *tview.TextView {
 
 	_, _ = fmt.Fprintf(tabs, `%d ["%d"][white]%s[white][""]  `, 1, 1, "Streams")
 	_, _ = fmt.Fprintf(tabs, `%d ["%d"][white]%s[white][""]  `, 2, 2, "Listeners")
+	_, _ = fmt.Fprintf(tabs, `%d ["%d"][white]%s[white][""]  `, 3, 3, "Groups")
+	_, _ = fmt.Fprintf(tabs, `%d ["%d"][white]%s[white][""]  `, 4, 4, "Dashboard")
+	_, _ = fmt.Fprintf(tabs, `%d ["%d"][white]%s[white][""]  `, 5, 5, "Watches")
 
 	return tabs
 }
 
+// makeStatusBar creates the line at the bottom of the screen
+// that shows notices about monitor state
+func makeStatusBar() *tview.TextView {
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	status.SetBackgroundColor(color)
+
+	return status
+}
+
 // makeStreamsPage prepares the content of the Streams page
 // where it has active Events list, messages list of a selected Event
 // and a content of a message
@@ -109,17 +198,25 @@ func makeStreamsPage(t *Terminal) *tview.Flex {
 		}
 	})
 
+	header := makeStreamHeader()
+	filter := makeFilterInput(t)
+
 	t.app.SetFocus(events)
 	flex := tview.NewFlex().
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(events, 0, 1, true).
-			AddItem(messages, 0, 1, false), 0, 1, true).
-		AddItem(text, 0, 3, false)
+			AddItem(messages, 0, 1, false).
+			AddItem(filter, 1, 1, false), 0, 1, true).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(header, 7, 1, false).
+			AddItem(text, 0, 1, false), 0, 3, false)
 	flex.SetBackgroundColor(color)
 
 	t.streams = events
 	t.messages = messages
 	t.messageContent = text
+	t.streamHeader = header
+	t.filterInput = filter
 
 	return flex
 }
@@ -138,6 +235,7 @@ func makeListenersPage(t *Terminal, withListener bool) *tview.Flex {
 		})
 		_, _ = fmt.Fprint(text, "Artisan not detected. Listening is not available.")
 
+		t.listenersOutput = text
 		flex.AddItem(text, 0, 3, false)
 
 		return flex
@@ -170,41 +268,103 @@ func makeListenersPage(t *Terminal, withListener bool) *tview.Flex {
 }
 
 // BindMonitor binds terminal actions (view updates) to streamer monitor events.
+// Monitor events come from its goroutines, so all view updates are queued to the application.
 func (t *Terminal) BindMonitor(monitor *pkg.Monitor) {
-	monitor.OnNewStream(func(stream pkg.Stream) {
-		t.streams.AddItem(stream.Name, fmt.Sprintf("- messages count: %d", stream.MessagesCount()), 0, nil)
+	t.monitor = monitor
+	t.bindGroups(monitor)
+	t.bindWatches(monitor)
+	go t.refreshMetrics()
+	go t.refreshDashboard()
+
+	monitor.OnNewStream(func(stream *pkg.Stream) {
+		t.app.QueueUpdateDraw(func() {
+			main, secondary := streamItem(stream)
+			t.streams.AddItem(main, secondary, 0, nil)
+		})
 	})
 
-	monitor.OnNewMessage(func(stream pkg.Stream, message pkg.StreamMessage) {
-		key := t.FindStreamKey(stream)
-		if key < 0 {
-			return
-		}
+	monitor.OnNewMessage(func(stream *pkg.Stream, message pkg.StreamMessage) {
+		t.app.QueueUpdateDraw(func() {
+			key := t.FindStreamKey(stream.Name)
+			if key < 0 {
+				return
+			}
+
+			main, secondary := streamItem(stream)
+			t.streams.SetItemText(key, main, secondary)
+
+			if t.activeStream == stream && t.messages.GetFocusable().HasFocus() && t.matchFilter(stream, message) {
+				t.messages.AddItem(t.messageRow(message), stream.Name, 0, nil)
+				for t.messages.GetItemCount() > stream.MessagesCount() {
+					t.messages.RemoveItem(0)
+				}
+			}
+		})
+	})
 
+	monitor.OnStreamRemoved(func(stream *pkg.Stream) {
+		t.refreshStream(stream)
+	})
+
+	monitor.OnStreamRecreated(func(stream *pkg.Stream) {
+		t.refreshStream(stream)
+	})
+
+	monitor.OnStreamTrimmed(func(stream *pkg.Stream, evicted []pkg.StreamID) {
+		t.refreshStream(stream)
+	})
+
+	monitor.OnStreamForgotten(func(stream *pkg.Stream) {
 		t.app.QueueUpdateDraw(func() {
-			t.streams.SetItemText(key, stream.Name, fmt.Sprintf("- messages count: %d", stream.MessagesCount()))
+			if key := t.FindStreamKey(stream.Name); key >= 0 {
+				t.streams.RemoveItem(key)
+			}
+
+			if t.activeStream == stream {
+				t.activeStream = nil
+				t.selected = nil
+				t.messages.Clear()
+				t.messageContent.Clear()
+			}
 		})
+	})
 
-		if t.activeStream.Name == stream.Name && t.messages.GetFocusable().HasFocus() {
-			t.messages.AddItem(message.ID, stream.Name, 0, nil)
-		}
+	monitor.OnDiscoveryFallback(func(err error) {
+		t.setStatus(fmt.Sprintf("[yellow]Keyspace notifications unavailable, discovering streams by polling: %v", err))
+	})
+
+	monitor.OnResync(func(missed time.Duration) {
+		t.setStatus(fmt.Sprintf("[yellow]Resynced after %.0f missed seconds", missed.Seconds()))
+	})
+
+	monitor.OnStreamRenamed(func(stream *pkg.Stream, oldName string) {
+		t.app.QueueUpdateDraw(func() {
+			if key := t.FindStreamKey(stream.Name); key >= 0 {
+				t.streams.RemoveItem(key)
+			}
+
+			key := t.FindStreamKey(oldName)
+			if key < 0 {
+				return
+			}
+
+			main, secondary := streamItem(stream)
+			t.streams.SetItemText(key, main, secondary)
+			if t.activeStream != nil && t.activeStream.Name == oldName {
+				t.showMessages(stream)
+			}
+		})
 	})
 
 	t.streams.SetSelectedFunc(func(key int, main, secondary string, short rune) {
-		s := monitor.Streams.Find(main)
+		s := monitor.Streams.Find(itemName(main))
 		if s == nil {
 			return
 		}
 
-		t.messages.SetTitle(main)
-		t.messages.Clear()
-		for _, m := range s.GetMessagesList() {
-			t.messages.AddItem(m, s.Name, 0, nil)
-		}
-
+		t.showMessages(s)
 		t.app.QueueUpdate(func() {})
 		t.app.SetFocus(t.messages)
-		t.activeStream = *s
 	})
 
 	t.messages.SetChangedFunc(func(key int, main, secondary string, short rune) {
@@ -213,14 +373,20 @@ func (t *Terminal) BindMonitor(monitor *pkg.Monitor) {
 			return
 		}
 
-		m, err := s.GetMessage(main)
+		id, err := messageID(main)
+		if err != nil {
+			pkg.LogWarning(err.Error())
+			return
+		}
+
+		m, err := s.GetMessage(id)
 		if err != nil {
 			pkg.LogWarning(err.Error())
 			return
 		}
 
 		t.messageContent.Clear()
-		_, _ = fmt.Fprint(t.messageContent, m.ParseContent())
+		_, _ = fmt.Fprint(t.messageContent, t.renderMessage(m, s.Name))
 	})
 
 	t.messages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -228,18 +394,36 @@ func (t *Terminal) BindMonitor(monitor *pkg.Monitor) {
 			_ = clipboard.WriteAll(strings.TrimSpace(t.messageContent.GetText(false)))
 		}
 
-		return event
+		if event.Key() == tcell.KeyUp && t.messages.GetCurrentItem() == 0 && t.activeStream != nil && !t.loadingHistory {
+			t.loadingHistory = true
+			go t.loadOlderMessages(monitor, t.activeStream)
+		}
+
+		return t.streamsPageKeys(event)
 	})
+
+	t.streams.SetInputCapture(t.streamsPageKeys)
+}
+
+// streamsPageKeys handles filter, export, compose, republish and destructive actions shortcuts of the streams page lists.
+func (t *Terminal) streamsPageKeys(event *tcell.EventKey) *tcell.EventKey {
+	for _, keys := range []func(event *tcell.EventKey) *tcell.EventKey{t.filterKeys, t.exportKeys, t.composeKeys, t.replayKeys, t.adminKeys} {
+		if event = keys(event); event == nil {
+			return nil
+		}
+	}
+
+	return event
 }
 
 // FindStreamKey returns match on a stream name from current streams list in terminal view.
-func (t *Terminal) FindStreamKey(stream pkg.Stream) int {
-	keys := t.streams.FindItems(stream.Name, "", true, false)
+func (t *Terminal) FindStreamKey(name string) int {
+	keys := t.streams.FindItems(name, "", true, false)
 
 	var m string
 	for _, k := range keys {
 		m, _ = t.streams.GetItemText(k)
-		if m == stream.Name {
+		if itemName(m) == name {
 			return k
 		}
 	}
@@ -247,48 +431,181 @@ func (t *Terminal) FindStreamKey(stream pkg.Stream) int {
 	return -1
 }
 
+// SetReadOnly marks terminal as working in read-only mode, which is always shown on the status bar.
+// Terminal has to be created without listener.
+func (t *Terminal) SetReadOnly() {
+	t.readOnly = true
+	t.listenersOutput.Clear()
+	_, _ = fmt.Fprint(t.listenersOutput, "Read-only mode is active. Listening is disabled.")
+	t.setStatus("Read-only mode, listening and writing to Redis are disabled")
+}
+
+// setStatus shows notice on the status bar, prefixed with current time
+// and read-only mode indicator when it is active.
+func (t *Terminal) setStatus(text string) {
+	t.app.QueueUpdateDraw(func() {
+		t.status.Clear()
+		if t.readOnly {
+			_, _ = fmt.Fprint(t.status, "[black:yellow] READ-ONLY [-:-] ")
+		}
+		_, _ = fmt.Fprintf(t.status, "%s %s", time.Now().Format("15:04:05"), text)
+	})
+}
+
+// loadOlderMessages of a stream, when user scrolls past the top of messages list.
+func (t *Terminal) loadOlderMessages(monitor *pkg.Monitor, s *pkg.Stream) {
+	n, err := monitor.LoadOlder(s, t.HistoryPage)
+	if err != nil {
+		pkg.LogWarning(err.Error())
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		t.loadingHistory = false
+		if n == 0 || t.activeStream != s {
+			return
+		}
+
+		t.showMessages(s)
+		t.messages.SetCurrentItem(n - 1)
+	})
+}
+
+// refreshStream updates stream row on the streams list
+// and its messages list when the stream is the active one.
+func (t *Terminal) refreshStream(stream *pkg.Stream) {
+	t.app.QueueUpdateDraw(func() {
+		key := t.FindStreamKey(stream.Name)
+		if key < 0 {
+			return
+		}
+
+		main, secondary := streamItem(stream)
+		t.streams.SetItemText(key, main, secondary)
+		if t.activeStream == stream {
+			t.showMessages(stream)
+		}
+	})
+}
+
+// showMessages of a stream on the messages list, making it the active stream.
+// Keeps current item and selected messages when the same stream is shown again.
+func (t *Terminal) showMessages(stream *pkg.Stream) {
+	current := 0
+	if t.activeStream == stream {
+		current = t.messages.GetCurrentItem()
+	} else {
+		t.selected = nil
+	}
+
+	t.messages.SetTitle(stream.Name)
+	if t.filter != nil {
+		t.messages.SetTitle(stream.Name + " (filtered)")
+	}
+
+	t.messages.Clear()
+	for _, id := range stream.GetMessagesList() {
+		m, err := stream.GetMessage(id)
+		if err != nil || !t.matchFilter(stream, *m) {
+			continue
+		}
+
+		t.messages.AddItem(t.messageRow(*m), stream.Name, 0, nil)
+	}
+
+	t.messages.SetCurrentItem(current)
+	if t.activeStream != stream {
+		go t.loadStreamDetails(stream)
+	}
+
+	t.activeStream = stream
+	t.showStreamHeader()
+}
+
+// streamItem returns main and secondary text of a stream row on the streams list.
+// Deleted streams are greyed out.
+func streamItem(stream *pkg.Stream) (string, string) {
+	if stream.Deleted() {
+		return fmt.Sprintf("[grey]%s", stream.Name), fmt.Sprintf("[grey]- deleted, messages count: %d", stream.MessagesCount())
+	}
+
+	metrics := stream.Metrics(time.Now())
+
+	return stream.Name, fmt.Sprintf("- messages count: %d, %s, last: %s", stream.MessagesCount(), formatRate(metrics.Rate1m), since(metrics.LastMessage, time.Now()))
+}
+
+// messageItem returns main text of a message row on the messages list,
+// with message ID followed by its local wall-clock time. Evicted messages are greyed out.
+func messageItem(message pkg.StreamMessage) string {
+	created := message.ID.Time().Local().Format("2006-01-02 15:04:05.000")
+	if message.Evicted {
+		return fmt.Sprintf("[grey]%s  %s (evicted)", message.ID, created)
+	}
+
+	return fmt.Sprintf("%s  [grey]%s", message.ID, created)
+}
+
+// itemName strips formatting of a streams list row, returning stream name.
+func itemName(main string) string {
+	main = strings.TrimPrefix(main, "[grey]")
+
+	return strings.TrimSuffix(main, " (evicted)")
+}
+
+// messageID parses message ID from a messages list row.
+func messageID(main string) (pkg.StreamID, error) {
+	fields := strings.Fields(strings.TrimPrefix(main, "[grey]"))
+	if len(fields) == 0 {
+		return pkg.StreamID{}, fmt.Errorf("no message ID in %q", main)
+	}
+
+	return pkg.ParseStreamID(fields[0])
+}
+
 func (t *Terminal) BindListener(l *pkg.Listener) {
+	t.listener = l
 	go func() {
 		for {
 			select {
+			case <-t.done:
+				return
 			case <-t.printDefaultOutput:
-				if l.Items == nil {
-					continue
-				}
-
-				main, _ := t.listeners.GetItemText(t.listeners.GetCurrentItem())
-				lis, ok := l.Items[main]
-				if !ok {
-					continue
-				}
-
-				t.listenersOutput.Clear()
-				_, _ = fmt.Fprint(t.listenersOutput, lis.ParseOutput())
+				t.app.QueueUpdateDraw(func() {
+					main, _ := t.listeners.GetItemText(t.listeners.GetCurrentItem())
+					lis, ok := l.Find(main)
+					if !ok {
+						return
+					}
+
+					t.listenersOutput.Clear()
+					_, _ = fmt.Fprint(t.listenersOutput, lis.ParseOutput())
+				})
 			}
 		}
 	}()
 
 	l.OnNewListener(func(listener pkg.StreamListener) {
-		t.listeners.AddItem(listener.Name, fmt.Sprintf("Status: %s", listener.Status()), 0, nil)
+		t.app.QueueUpdateDraw(func() {
+			t.listeners.AddItem(listener.Name, fmt.Sprintf("Status: %s", listener.Status()), 0, nil)
+		})
 	})
 
 	l.OnListenerChange(func(listener pkg.StreamListener, lastOutput string) {
-		key := t.FindListenerKey(listener.Name)
-		if key < 0 {
-			return
-		}
+		t.app.QueueUpdateDraw(func() {
+			key := t.FindListenerKey(listener.Name)
+			if key < 0 {
+				return
+			}
 
-		if key == t.listeners.GetCurrentItem() {
-			_, _ = fmt.Fprint(t.listenersOutput, lastOutput)
-		}
+			if key == t.listeners.GetCurrentItem() {
+				_, _ = fmt.Fprint(t.listenersOutput, lastOutput)
+			}
 
-		t.app.QueueUpdateDraw(func() {
 			t.listeners.SetItemText(key, listener.Name, fmt.Sprintf("Status: %s", listener.Status()))
 		})
 	})
 
 	t.listeners.SetChangedFunc(func(key int, main string, secondary string, short rune) {
-		lis, ok := l.Items[main]
+		lis, ok := l.Find(main)
 		if !ok {
 			return
 		}
@@ -0,0 +1,142 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"os"
+	"swarm/pkg"
+)
+
+// watchesPage is a name of the Watches page.
+const watchesPage = "5"
+
+// matchRowsLimit is the highest number of rows on the matches list.
+const matchRowsLimit = 1000
+
+// makeWatchesPage prepares the content of the Watches page
+// where it shows watched filters and messages matching them
+func makeWatchesPage(t *Terminal) *tview.Flex {
+	t.watches = tview.NewList().ShowSecondaryText(false)
+	t.watches.SetBorder(true).SetBackgroundColor(color)
+	t.watches.SetSelectedBackgroundColor(tcell.ColorWhite)
+	t.watches.SetSelectedTextColor(color)
+	t.watches.SetTitle("Watches (a: add, d: remove)")
+
+	t.matches = tview.NewList().ShowSecondaryText(true)
+	t.matches.SetBorder(true).SetBackgroundColor(color)
+	t.matches.SetSelectedBackgroundColor(tcell.ColorWhite)
+	t.matches.SetSelectedTextColor(color)
+	t.matches.SetSecondaryTextColor(tcell.ColorWhite)
+	t.matches.SetTitle("Matches")
+	t.matches.SetDoneFunc(func() {
+		t.app.SetFocus(t.watches)
+	})
+
+	flex := tview.NewFlex().
+		AddItem(t.watches, 0, 1, true).
+		AddItem(t.matches, 0, 2, false)
+	flex.SetBackgroundColor(color)
+
+	return flex
+}
+
+// bindWatches binds watches page actions to monitor and shows watch matches as they come,
+// with a notice on the status bar and a terminal bell.
+func (t *Terminal) bindWatches(monitor *pkg.Monitor) {
+	for _, w := range monitor.Watches() {
+		t.addWatchRow(w)
+	}
+
+	monitor.OnWatchMatch(func(match pkg.WatchMatch) {
+		_, _ = fmt.Fprint(os.Stdout, "\a")
+		t.setStatus(fmt.Sprintf("[yellow]Watch %s matched message %s of %s stream", match.Watch.Query, match.Message.ID, match.Stream))
+		t.app.QueueUpdateDraw(func() {
+			t.addMatchRow(match)
+		})
+	})
+
+	t.watches.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+
+		switch event.Rune() {
+		case 'a':
+			t.prompt("Watch messages", "Filter", "Watch", nil, func(query string) {
+				if query == "" {
+					return
+				}
+
+				w, err := monitor.AddWatch(query)
+				if err != nil {
+					go t.setStatus(fmt.Sprintf("[red]%s", err))
+					return
+				}
+
+				t.addWatchRow(w)
+			})
+			return nil
+		case 'd':
+			key := t.watches.GetCurrentItem()
+			if key >= len(t.watchRows) {
+				return nil
+			}
+
+			w := t.watchRows[key]
+			t.confirm(fmt.Sprintf("Stop watching %s?", w.Query), "Remove", func() {
+				monitor.RemoveWatch(w)
+				t.removeWatchRow(w)
+			})
+			return nil
+		}
+
+		return event
+	})
+
+	t.watches.SetSelectedFunc(func(key int, main, secondary string, short rune) {
+		t.app.SetFocus(t.matches)
+	})
+
+	t.matches.SetSelectedFunc(func(key int, main, secondary string, short rune) {
+		if key >= len(t.matchRows) {
+			return
+		}
+
+		t.jumpToMessage(t.matchRows[key].Stream, t.matchRows[key].Message.ID)
+	})
+}
+
+// addWatchRow to the watches list.
+func (t *Terminal) addWatchRow(w *pkg.Watch) {
+	t.watchRows = append(t.watchRows, w)
+	t.watches.AddItem(tview.Escape(w.Query), "", 0, nil)
+}
+
+// removeWatchRow from the watches list.
+func (t *Terminal) removeWatchRow(w *pkg.Watch) {
+	for i, row := range t.watchRows {
+		if row == w {
+			t.watchRows = append(t.watchRows[:i], t.watchRows[i+1:]...)
+			t.watches.RemoveItem(i)
+			return
+		}
+	}
+}
+
+// addMatchRow to the matches list, dropping the oldest rows over the limit of matches kept by monitor.
+func (t *Terminal) addMatchRow(match pkg.WatchMatch) {
+	t.matchRows = append(t.matchRows, match)
+	t.matches.AddItem(
+		fmt.Sprintf("%s  %s  %s", match.Time.Format("15:04:05"), match.Stream, match.Message.ID),
+		"  "+tview.Escape(match.Watch.Query)+": "+preview(match.Message),
+		0, nil,
+	)
+
+	for len(t.matchRows) > matchRowsLimit {
+		t.matchRows = t.matchRows[1:]
+		t.matches.RemoveItem(0)
+	}
+
+	t.matches.SetTitle(fmt.Sprintf("Matches (%d)", len(t.matchRows)))
+}
@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Trim strategies of XTRIM command.
+const (
+	// TrimMaxLen keeps given number of the newest messages
+	TrimMaxLen = "MAXLEN"
+	// TrimMinID drops messages with IDs lower than given one (Redis 6.2+)
+	TrimMinID = "MINID"
+)
+
+// DeleteMessages of a stream with XDEL command. Returns number of deleted messages.
+func (m *Monitor) DeleteMessages(stream string, ids ...StreamID) (int64, error) {
+	args := []interface{}{"XDEL", stream}
+	for _, id := range idStrings(ids) {
+		args = append(args, id)
+	}
+
+	return m.destructiveInt(args...)
+}
+
+// Trim stream with XTRIM command by MAXLEN or MINID strategy, with exact threshold.
+// Returns number of deleted messages.
+func (m *Monitor) Trim(stream, strategy, threshold string) (int64, error) {
+	switch strategy {
+	case TrimMaxLen:
+		if _, err := strconv.ParseUint(threshold, 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid MAXLEN %q, expected number of messages", threshold)
+		}
+	case TrimMinID:
+		if _, err := ParseStreamID(threshold); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("unknown trim strategy %q, expected %s or %s", strategy, TrimMaxLen, TrimMinID)
+	}
+
+	return m.destructiveInt("XTRIM", stream, strategy, threshold)
+}
+
+// DeleteStream key with all its messages and consumer groups.
+func (m *Monitor) DeleteStream(stream string) error {
+	_, err := m.destructive("DEL", stream)
+
+	return err
+}
+
+// SetGroupID sets last delivered ID of a consumer group with XGROUP SETID command.
+// ID can be "$" for the last message of the stream.
+func (m *Monitor) SetGroupID(stream, group, id string) error {
+	if id != "$" {
+		if _, err := ParseStreamID(id); err != nil {
+			return err
+		}
+	}
+
+	_, err := m.destructive("XGROUP", "SETID", stream, group, id)
+
+	return err
+}
+
+// DestroyGroup of a stream with XGROUP DESTROY command, dropping its pending entries.
+func (m *Monitor) DestroyGroup(stream, group string) error {
+	_, err := m.destructiveInt("XGROUP", "DESTROY", stream, group)
+
+	return err
+}
+
+// DeleteConsumer of a group with XGROUP DELCONSUMER command.
+// Returns number of pending entries the consumer had, which are dropped.
+func (m *Monitor) DeleteConsumer(stream, group, consumer string) (int64, error) {
+	return m.destructiveInt("XGROUP", "DELCONSUMER", stream, group, consumer)
+}
+
+// destructive command sent to Redis, unless Monitor is read-only. The command is recorded in Audit log.
+func (m *Monitor) destructive(args ...interface{}) (interface{}, error) {
+	if err := m.writable(); err != nil {
+		return nil, err
+	}
+
+	res, err := m.Redis.Do(args...).Result()
+	if m.Audit != nil {
+		if aerr := m.Audit.Record(args, res, err); aerr != nil {
+			LogError(fmt.Sprintf("failed to write audit log, err: %v", aerr))
+		}
+	}
+
+	return res, err
+}
+
+// destructiveInt command with integer reply.
+func (m *Monitor) destructiveInt(args ...interface{}) (int64, error) {
+	res, err := m.destructive(args...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := res.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected %s reply %v", args[0], res)
+	}
+
+	return n, nil
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -53,14 +54,19 @@ func (a *Artisan) Exec(args ...string) ([]byte, *exec.Cmd, error) {
 }
 
 // ExecPipe runs artisan command constantly gathering all its output if the command is still running.
-// Usable by listeners/queues.
-func (a *Artisan) ExecPipe(handler func(output string, cms *exec.Cmd) error, args ...string) (*exec.Cmd, error) {
-	cmd := exec.Command(a.base, a.parseArgs(args)...)
+// Usable by listeners/queues. The command is killed when context is done or handler returns an error,
+// and it is always waited for, so no process is left behind.
+// Returns an error when the command cannot be started, e.g. when context is already done.
+func (a *Artisan) ExecPipe(ctx context.Context, handler func(output string, cms *exec.Cmd) error, args ...string) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, a.base, a.parseArgs(args)...)
 
 	stdout, err := cmd.StdoutPipe()
-	err = cmd.Start()
 	if err != nil {
-		panic(err)
+		return cmd, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return cmd, err
 	}
 
 	buff := make([]byte, 1024)
@@ -70,6 +76,7 @@ func (a *Artisan) ExecPipe(handler func(output string, cms *exec.Cmd) error, arg
 		if n > 0 {
 			err := handler(string(buff[:n]), cmd)
 			if err != nil {
+				_ = cmd.Process.Kill()
 				break
 			}
 		}
@@ -0,0 +1,31 @@
+package pkg
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestArtisan_ExecPipe(t *testing.T) {
+	a := &Artisan{base: "sh", args: []string{"-c", "echo started; exec sleep 30"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	cmd, err := a.ExecPipe(ctx, func(output string, cmd *exec.Cmd) error {
+		cancel()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExecPipe() error = %v", err)
+	}
+
+	if time.Since(start) > time.Second*5 || cmd.ProcessState == nil || cmd.ProcessState.Success() {
+		t.Errorf("ExecPipe() did not kill the command when context was cancelled, state: %v", cmd.ProcessState)
+	}
+
+	if _, err := a.ExecPipe(ctx, func(output string, cmd *exec.Cmd) error { return nil }); err == nil {
+		t.Error("ExecPipe() with cancelled context error = nil, want error")
+	}
+}
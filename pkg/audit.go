@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog appends destructive commands run by Monitor to a file, one JSON document per line.
+// It is safe for concurrent use.
+type AuditLog struct {
+	// Path of the audit log file
+	Path string
+	// User running commands
+	User string
+	// Connection to Redis the commands are sent to, e.g. host:port
+	Connection string
+	mu         sync.Mutex
+}
+
+// auditEntry is a single line of the audit log.
+type auditEntry struct {
+	Time       string `json:"time"`
+	User       string `json:"user"`
+	Connection string `json:"connection"`
+	Command    string `json:"command"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewAuditLog of commands sent to Redis connection, written to a file by current system user.
+func NewAuditLog(path, connection string) *AuditLog {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+
+	return &AuditLog{Path: path, User: name, Connection: connection}
+}
+
+// Record command with its result, or error when it failed.
+func (a *AuditLog) Record(command []interface{}, result interface{}, err error) error {
+	entry := auditEntry{
+		Time:       time.Now().Format(time.RFC3339),
+		User:       a.User,
+		Connection: a.Connection,
+		Command:    commandString(command),
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Result = fmt.Sprint(result)
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	f, err := os.OpenFile(a.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// commandString formats command as redis-cli would take it, quoting arguments with special characters.
+func commandString(command []interface{}) string {
+	args := make([]string, 0, len(command))
+	for _, a := range command {
+		arg := fmt.Sprint(a)
+		if arg == "" || strings.IndexFunc(arg, special) >= 0 {
+			arg = redisQuote(arg)
+		}
+
+		args = append(args, arg)
+	}
+
+	return strings.Join(args, " ")
+}
+
+// special checks if character of redis-cli argument requires quoting.
+func special(r rune) bool {
+	return r <= ' ' || r >= 0x7f || r == '"' || r == '\\' || r == '\''
+}
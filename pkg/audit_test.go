@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog_Record(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := &AuditLog{Path: filepath.Join(dir, "audit.log"), User: "alice", Connection: "localhost:6379"}
+	if err := a.Record([]interface{}{"XDEL", "orders", "1-0"}, int64(1), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Record([]interface{}{"XGROUP", "DESTROY", "my orders", "g"}, nil, errors.New("ERR no such key")); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(a.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, want 2", len(lines))
+	}
+
+	want := []auditEntry{
+		{User: "alice", Connection: "localhost:6379", Command: `XDEL orders 1-0`, Result: "1"},
+		{User: "alice", Connection: "localhost:6379", Command: `XGROUP DESTROY "my orders" g`, Error: "ERR no such key"},
+	}
+	for i, line := range lines {
+		var got auditEntry
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Time == "" {
+			t.Errorf("entry %d has no time", i)
+		}
+		got.Time = ""
+		if got != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestMonitor_destructive(t *testing.T) {
+	m := &Monitor{ReadOnly: true}
+	if err := m.DeleteStream("orders"); err != ErrReadOnly {
+		t.Errorf("DeleteStream() error = %v, want ErrReadOnly", err)
+	}
+
+	m.ReadOnly = false
+	if _, err := m.Trim("orders", TrimMaxLen, "-1"); err == nil {
+		t.Error("Trim() with invalid MAXLEN error = nil, want error")
+	}
+	if _, err := m.Trim("orders", "LEN", "1"); err == nil {
+		t.Error("Trim() with unknown strategy error = nil, want error")
+	}
+	if err := m.SetGroupID("orders", "g", "last"); err == nil {
+		t.Error("SetGroupID() with invalid ID error = nil, want error")
+	}
+}
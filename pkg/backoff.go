@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"time"
+)
+
+// backoff computes exponentially growing delays between retries of a failing operation.
+type backoff struct {
+	min      time.Duration
+	max      time.Duration
+	current  time.Duration
+	failedAt time.Time
+}
+
+// newBackoff with delays growing from min up to max.
+func newBackoff(min, max time.Duration) *backoff {
+	return &backoff{min: min, max: max}
+}
+
+// Fail records failed attempt and returns how long to wait before the next one.
+func (b *backoff) Fail() time.Duration {
+	if b.current == 0 {
+		b.current = b.min
+		b.failedAt = time.Now()
+	} else {
+		b.current *= 2
+	}
+
+	if b.current > b.max {
+		b.current = b.max
+	}
+
+	return b.current
+}
+
+// Reset after successful attempt. Returns for how long the operation was failing,
+// or zero when it did not fail before.
+func (b *backoff) Reset() time.Duration {
+	if b.current == 0 {
+		return 0
+	}
+
+	b.current = 0
+
+	return time.Since(b.failedAt)
+}
@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Fail(t *testing.T) {
+	b := newBackoff(time.Millisecond*100, time.Millisecond*500)
+	want := []time.Duration{
+		time.Millisecond * 100,
+		time.Millisecond * 200,
+		time.Millisecond * 400,
+		time.Millisecond * 500,
+		time.Millisecond * 500,
+	}
+
+	for i, w := range want {
+		if got := b.Fail(); got != w {
+			t.Errorf("Fail() #%d = %v, want %v", i, got, w)
+		}
+	}
+
+	if got := b.Reset(); got <= 0 {
+		t.Errorf("Reset() = %v, want positive duration", got)
+	}
+
+	if got := b.Reset(); got != 0 {
+		t.Errorf("Reset() = %v, want 0", got)
+	}
+
+	if got := b.Fail(); got != want[0] {
+		t.Errorf("Fail() after Reset() = %v, want %v", got, want[0])
+	}
+}
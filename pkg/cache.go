@@ -0,0 +1,51 @@
+package pkg
+
+// keyCache is a bounded set of keys. When it is full, the oldest key
+// is forgotten to make room for the new one.
+type keyCache struct {
+	size  int
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+// newKeyCache creates cache holding at most size keys.
+func newKeyCache(size int) *keyCache {
+	if size < 1 {
+		size = 1
+	}
+
+	return &keyCache{
+		size: size,
+		keys: make(map[string]struct{}),
+	}
+}
+
+// Add key to the cache, evicting the oldest one when cache is full.
+func (c *keyCache) Add(key string) {
+	if c.Has(key) {
+		return
+	}
+
+	if len(c.order) < c.size {
+		c.order = append(c.order, key)
+	} else {
+		delete(c.keys, c.order[c.next])
+		c.order[c.next] = key
+		c.next = (c.next + 1) % c.size
+	}
+
+	c.keys[key] = struct{}{}
+}
+
+// Has checks if key is in the cache.
+func (c *keyCache) Has(key string) bool {
+	_, ok := c.keys[key]
+
+	return ok
+}
+
+// Len returns number of keys currently cached.
+func (c *keyCache) Len() int {
+	return len(c.keys)
+}
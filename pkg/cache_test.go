@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"testing"
+)
+
+func TestKeyCache_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		keys    []string
+		wantHas []string
+		wantNot []string
+	}{
+		{"keeps keys below limit",
+			3,
+			[]string{"a", "b", "c"},
+			[]string{"a", "b", "c"},
+			nil,
+		},
+		{"evicts oldest keys over limit",
+			2,
+			[]string{"a", "b", "c", "d"},
+			[]string{"c", "d"},
+			[]string{"a", "b"},
+		},
+		{"does not duplicate keys",
+			2,
+			[]string{"a", "a", "b"},
+			[]string{"a", "b"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newKeyCache(tt.size)
+			for _, k := range tt.keys {
+				c.Add(k)
+			}
+
+			for _, k := range tt.wantHas {
+				if !c.Has(k) {
+					t.Errorf("Has(%s) = false, want true", k)
+				}
+			}
+
+			for _, k := range tt.wantNot {
+				if c.Has(k) {
+					t.Errorf("Has(%s) = true, want false", k)
+				}
+			}
+
+			if c.Len() > tt.size {
+				t.Errorf("Len() = %v, want at most %v", c.Len(), tt.size)
+			}
+		})
+	}
+}
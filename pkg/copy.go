@@ -0,0 +1,75 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CopyStreams keeps copies of streams added with CopyStream under names with given prefix, adding every message
+// read by Monitor to the copy with its original ID. Copies hold about MessagesLimit messages, so listeners can read
+// them without affecting consumers of the original streams. Copies are named per instance, see CopyName,
+// and streams with the prefix are not discovered. It must be called before Monitor is started.
+func (m *Monitor) CopyStreams(prefix string) {
+	m.copyPrefix = prefix
+	m.OnNewMessage(func(stream *Stream, message StreamMessage) {
+		if !m.isCopied(stream.Name) {
+			return
+		}
+
+		p := Republication(m.CopyName(stream.Name), message)
+		p.ID = message.ID
+		p.MaxLen = int64(m.MessagesLimit)
+		if _, err := m.Publish(p); err != nil && !isOlderID(err) {
+			LogWarning(fmt.Sprintf("Failed to copy %s message of %s stream: %v", message.ID, stream.Name, err))
+		}
+	})
+}
+
+// CopyStream starts copying new messages of a stream, see CopyStreams.
+func (m *Monitor) CopyStream(name string) {
+	m.copyMu.Lock()
+	defer m.copyMu.Unlock()
+
+	if m.copied == nil {
+		m.copied = make(map[string]bool)
+	}
+	m.copied[name] = true
+}
+
+// CopyName of a stream, made of copies prefix and InstanceName, so instances sharing the prefix
+// never write to or delete copies of each other.
+func (m *Monitor) CopyName(name string) string {
+	return m.copyPrefix + InstanceName() + ":" + name
+}
+
+// DeleteCopies of all copied streams, which stops copying them.
+func (m *Monitor) DeleteCopies() {
+	m.copyMu.Lock()
+	copied := m.copied
+	m.copied = nil
+	m.copyMu.Unlock()
+
+	for name := range copied {
+		if err := m.DeleteStream(m.CopyName(name)); err != nil {
+			LogWarning(fmt.Sprintf("Failed to delete copy of %s stream: %v", name, err))
+		}
+	}
+}
+
+// isCopy checks if a stream is a copy kept by any instance, copies are not monitored.
+func (m *Monitor) isCopy(name string) bool {
+	return m.copyPrefix != "" && strings.HasPrefix(name, m.copyPrefix)
+}
+
+// isCopied checks if new messages of a stream are copied.
+func (m *Monitor) isCopied(name string) bool {
+	m.copyMu.Lock()
+	defer m.copyMu.Unlock()
+
+	return m.copied[name]
+}
+
+// isOlderID checks if XADD failed as the message is already copied, e.g. when history is read again.
+func isOlderID(err error) bool {
+	return strings.Contains(err.Error(), "equal or smaller than the target stream top item")
+}
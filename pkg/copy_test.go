@@ -0,0 +1,29 @@
+package pkg
+
+import (
+	"testing"
+)
+
+func TestMonitor_addStream_copy(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	m.CopyStreams("swarm.")
+	m.addStream("swarm.orders")
+
+	if m.Streams.Find("swarm.orders") != nil {
+		t.Error("addStream() added copy of a stream")
+	}
+}
+
+func TestMonitor_CopyName(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	m.CopyStreams("swarm.")
+
+	name := m.CopyName("orders")
+	if want := "swarm." + InstanceName() + ":orders"; name != want {
+		t.Errorf("CopyName() = %s, want %s", name, want)
+	}
+
+	if !m.isCopy(name) || !m.isCopy("swarm.swarm-other-1:orders") {
+		t.Error("isCopy() = false for copies of this and other instances")
+	}
+}
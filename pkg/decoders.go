@@ -0,0 +1,362 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/vmihailenco/msgpack"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DecoderAuto is a name of automatic decoding, that detects encoding of a value.
+const DecoderAuto = "auto"
+
+// autoDepth limits how many nested encodings automatic decoding goes through.
+const autoDepth = 4
+
+// Decoder transforms raw message field value into a more readable one.
+type Decoder interface {
+	// Name of the decoder used in decoding rules.
+	Name() string
+	// Decode value. Returns error when value is not encoded the way decoder expects.
+	Decode(value []byte) ([]byte, error)
+}
+
+// DecoderRule selects decoders for fields of streams.
+type DecoderRule struct {
+	// Stream name pattern (as in path.Match), empty matches all streams.
+	Stream string
+	// Field name pattern (as in path.Match), empty matches all fields.
+	Field string
+	// Decoders applied one after another, DecoderAuto detects encoding.
+	Decoders []string
+}
+
+// Decoders decode message fields values by the first rule matching stream and field,
+// falling back to automatic decoding. Binary values that cannot be decoded are shown as hex dump.
+type Decoders struct {
+	decoders map[string]Decoder
+	rules    []DecoderRule
+}
+
+// NewDecoders with built-in JSON, base64, gzip, msgpack and hex dump decoders and given rules.
+func NewDecoders(rules []DecoderRule) (*Decoders, error) {
+	d := &Decoders{decoders: make(map[string]Decoder)}
+	for _, decoder := range []Decoder{jsonDecoder{}, base64Decoder{}, gzipDecoder{}, msgpackDecoder{}, hexDecoder{}} {
+		d.Register(decoder)
+	}
+
+	for _, r := range rules {
+		if err := d.AddRule(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// Register decoder, replacing the one with the same name.
+func (d *Decoders) Register(decoder Decoder) {
+	d.decoders[decoder.Name()] = decoder
+}
+
+// AddRule checked after already added ones. Returns error when rule uses unknown decoder or invalid pattern.
+func (d *Decoders) AddRule(rule DecoderRule) error {
+	for _, pattern := range []string{rule.Stream, rule.Field} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid decoder rule pattern %q: %v", pattern, err)
+		}
+	}
+
+	for _, name := range rule.Decoders {
+		if _, ok := d.decoders[name]; !ok && name != DecoderAuto {
+			return fmt.Errorf("unknown decoder %q", name)
+		}
+	}
+
+	d.rules = append(d.rules, rule)
+
+	return nil
+}
+
+// Decode value of a stream message field into readable text.
+// When decoders of a matching rule fail, the value is shown as it is.
+func (d *Decoders) Decode(stream, field string, value interface{}) string {
+	raw, ok := value.(string)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+
+	b := []byte(raw)
+	for _, name := range d.match(stream, field) {
+		var err error
+		if name == DecoderAuto {
+			b, _ = d.auto(b, autoDepth)
+			continue
+		}
+
+		b, err = d.decoders[name].Decode(b)
+		if err != nil {
+			return fmt.Sprintf("%s\n(%s decoder failed: %v)", text(raw), name, err)
+		}
+	}
+
+	return text(string(b))
+}
+
+// match returns decoders of the first rule matching stream and field, automatic decoding by default.
+func (d *Decoders) match(stream, field string) []string {
+	for _, r := range d.rules {
+		if matches(r.Stream, stream) && matches(r.Field, field) {
+			return r.Decoders
+		}
+	}
+
+	return []string{DecoderAuto}
+}
+
+// auto detects encoding of a value and decodes it, following nested encodings up to given depth.
+// Returns false when value was not decoded.
+func (d *Decoders) auto(b []byte, depth int) ([]byte, bool) {
+	if depth == 0 {
+		return b, false
+	}
+
+	if bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		if decoded, err := d.decoders["gzip"].Decode(b); err == nil {
+			decoded, _ = d.auto(decoded, depth-1)
+			return decoded, true
+		}
+	}
+
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if decoded, err := d.decoders["json"].Decode(trimmed); err == nil {
+			return decoded, true
+		}
+	}
+
+	if !printable(string(b)) {
+		if decoded, err := d.decoders["msgpack"].Decode(b); err == nil {
+			return decoded, true
+		}
+
+		return b, false
+	}
+
+	// Short words are valid base64 too, so decoded value is used only when it is encoded further.
+	if len(trimmed) >= 8 && len(trimmed)%4 == 0 {
+		if decoded, err := d.decoders["base64"].Decode(trimmed); err == nil {
+			if decoded, ok := d.auto(decoded, depth-1); ok {
+				return decoded, true
+			}
+		}
+	}
+
+	return b, false
+}
+
+// matches name with a pattern, empty pattern matches all names.
+func matches(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	ok, _ := path.Match(pattern, name)
+
+	return ok
+}
+
+// text returns value as it is when it is printable, hex dump of it otherwise.
+func text(value string) string {
+	if printable(value) {
+		return value
+	}
+
+	return hex.Dump([]byte(value))
+}
+
+// printable checks if value is a valid UTF-8 text without control characters other than whitespace.
+func printable(value string) bool {
+	if !utf8.ValidString(value) {
+		return false
+	}
+
+	for _, r := range value {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonDecoder pretty prints JSON with sorted object keys.
+type jsonDecoder struct{}
+
+func (jsonDecoder) Name() string {
+	return "json"
+}
+
+func (jsonDecoder) Decode(value []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return prettyJSON(v)
+}
+
+// base64Decoder decodes standard or URL base64 encoding, with or without padding.
+type base64Decoder struct{}
+
+func (base64Decoder) Name() string {
+	return "base64"
+}
+
+func (base64Decoder) Decode(value []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(value))
+	var err error
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		var decoded []byte
+		decoded, err = encoding.Strict().DecodeString(s)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+
+	return nil, err
+}
+
+// gzipDecoder decompresses gzip data.
+type gzipDecoder struct{}
+
+func (gzipDecoder) Name() string {
+	return "gzip"
+}
+
+func (gzipDecoder) Decode(value []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// msgpackDecoder decodes MessagePack map or array into pretty printed JSON.
+type msgpackDecoder struct{}
+
+func (msgpackDecoder) Name() string {
+	return "msgpack"
+}
+
+func (msgpackDecoder) Decode(value []byte) ([]byte, error) {
+	r := bytes.NewReader(value)
+	v, err := msgpack.NewDecoder(r).DecodeInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("unexpected data after MessagePack value")
+	}
+
+	switch v.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+	default:
+		return nil, fmt.Errorf("MessagePack value is not a map or an array")
+	}
+
+	return prettyJSON(jsonValue(v))
+}
+
+// hexDecoder shows value as hex dump.
+type hexDecoder struct{}
+
+func (hexDecoder) Name() string {
+	return "hex"
+}
+
+func (hexDecoder) Decode(value []byte) ([]byte, error) {
+	return []byte(hex.Dump(value)), nil
+}
+
+// prettyJSON encodes value as indented JSON, objects keys are sorted.
+func prettyJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// jsonValue converts decoded MessagePack value into one that can be encoded as JSON,
+// with maps keys as strings and binary data as text or base64.
+func jsonValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, i := range value {
+			m[fmt.Sprint(k)] = jsonValue(i)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, i := range value {
+			m[k] = jsonValue(i)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for k, i := range value {
+			list[k] = jsonValue(i)
+		}
+		return list
+	case []byte:
+		if printable(string(value)) {
+			return string(value)
+		}
+		return value
+	}
+
+	return v
+}
+
+// DecodedContent of the message, with fields sorted by name and their values decoded.
+func (m *StreamMessage) DecodedContent(decoders *Decoders, stream string) string {
+	var list []string
+	for k := range m.Content {
+		list = append(list, k)
+	}
+
+	sort.Strings(list)
+
+	var content string
+	for _, i := range list {
+		content += fmt.Sprintf("Field: %s\r\n", i)
+		content += fmt.Sprintf("Value: %s\r\n\r\n", decoders.Decode(stream, i, m.Content[i]))
+	}
+
+	return content
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/vmihailenco/msgpack"
+	"testing"
+)
+
+func gzipped(t *testing.T, value string) string {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(value)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestDecoders_Decode(t *testing.T) {
+	packed, err := msgpack.Marshal(map[string]interface{}{"b": 1, "a": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pretty := "{\n  \"a\": \"x\",\n  \"b\": 1\n}"
+	tests := []struct {
+		name  string
+		rules []DecoderRule
+		field string
+		value interface{}
+		want  string
+	}{
+		{"pretty prints JSON with sorted keys", nil, "data", `{"b":1,"a":"x"}`, pretty},
+		{"decodes gzipped JSON", nil, "data", gzipped(t, `{"b":1,"a":"x"}`), pretty},
+		{"decodes base64 of gzipped JSON", nil, "data", base64.StdEncoding.EncodeToString([]byte(gzipped(t, `{"b":1,"a":"x"}`))), pretty},
+		{"decodes msgpack", nil, "data", string(packed), pretty},
+		{"keeps text that only looks like base64", nil, "name", "testtest", "testtest"},
+		{"keeps invalid JSON", nil, "data", `{"a":`, `{"a":`},
+		{"dumps binary values", nil, "data", "\x00\x01", hex.Dump([]byte{0, 1})},
+		{"formats non string values", nil, "data", 5, "5"},
+		{"applies matching rule",
+			[]DecoderRule{{Stream: "orders.*", Field: "data", Decoders: []string{"base64"}}},
+			"data", "dGVzdA==", "test",
+		},
+		{"skips rule of other stream",
+			[]DecoderRule{{Stream: "users", Decoders: []string{"hex"}}},
+			"data", "test", "test",
+		},
+		{"notes failed decoder",
+			[]DecoderRule{{Field: "data", Decoders: []string{"gzip"}}},
+			"data", "test", "test\n(gzip decoder failed: unexpected EOF)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDecoders(tt.rules)
+			if err != nil {
+				t.Fatalf("NewDecoders() error = %v", err)
+			}
+			if got := d.Decode("orders.created", tt.field, tt.value); got != tt.want {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDecoders_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule DecoderRule
+	}{
+		{"unknown decoder", DecoderRule{Decoders: []string{"xml"}}},
+		{"invalid pattern", DecoderRule{Stream: "[", Decoders: []string{"json"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDecoders([]DecoderRule{tt.rule}); err == nil {
+				t.Errorf("NewDecoders() error = nil, want error")
+			}
+		})
+	}
+}
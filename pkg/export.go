@@ -0,0 +1,322 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Export formats.
+const (
+	// ExportNDJSON writes one JSON document per message line
+	ExportNDJSON = "ndjson"
+	// ExportCSV writes message ID and all fields found in exported messages as columns
+	ExportCSV = "csv"
+	// ExportXADD writes redis-cli script adding messages back with their IDs
+	ExportXADD = "xadd"
+)
+
+// ExportFormats lists all supported export formats.
+var ExportFormats = []string{ExportNDJSON, ExportCSV, ExportXADD}
+
+// exportPage is a number of messages read from Redis at once while exporting.
+const exportPage = 500
+
+// ExportOptions select messages of a stream that are exported and their format.
+type ExportOptions struct {
+	Stream string
+	// Format is one of ExportFormats
+	Format string
+	// From is the lowest exported ID, zero ID means the start of the stream
+	From StreamID
+	// To is the highest exported ID, zero ID means the end of the stream
+	To StreamID
+	// Filter of exported messages, all messages are exported when nil
+	Filter *Filter
+}
+
+// messagesPage reads messages starting from given ID, up to exportPage of them.
+type messagesPage func(start StreamID) ([]StreamMessage, error)
+
+// exportWriter writes exported messages in one of export formats.
+type exportWriter interface {
+	write(m StreamMessage) error
+	flush() error
+}
+
+// Export messages of a stream read directly from Redis, page by page, so they don't need to fit in memory.
+// CSV export reads messages twice, first to collect all fields names. Returns number of exported messages.
+func (m *Monitor) Export(w io.Writer, o ExportOptions) (int, error) {
+	return export(w, o, func(start StreamID) ([]StreamMessage, error) {
+		return m.rangePage(o.Stream, start, o.To)
+	})
+}
+
+// ParseRangeBound of exported messages, which is an ID (with optional sequence), a date time in RFC3339,
+// "2006-01-02 15:04:05" or "2006-01-02" format in local time zone, or empty, "-" and "+" for an open range.
+// The end bound includes all messages of its millisecond when sequence is not given,
+// and all messages of its second or day when date time is given with that precision.
+func ParseRangeBound(value string, end bool) (StreamID, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "-" || value == "+" {
+		return StreamID{}, nil
+	}
+
+	if id, err := ParseStreamID(value); err == nil {
+		if end && !strings.Contains(value, "-") {
+			id.Seq = math.MaxUint64
+		}
+
+		return id, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	next := t.Add(time.Millisecond)
+	if err == nil && t.Nanosecond() == 0 && !strings.Contains(value, ".") {
+		next = t.Add(time.Second)
+	}
+
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+		next = t.Add(time.Second)
+	}
+
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		next = t.AddDate(0, 0, 1)
+	}
+
+	if err != nil {
+		return StreamID{}, fmt.Errorf("invalid range bound %q, expected ID or date time", value)
+	}
+
+	if end {
+		return StreamID{Ms: timeMs(next) - 1, Seq: math.MaxUint64}, nil
+	}
+
+	return StreamID{Ms: timeMs(t)}, nil
+}
+
+// timeMs converts time to Unix time in milliseconds, as used by stream IDs.
+func timeMs(t time.Time) uint64 {
+	return uint64(t.UnixNano() / int64(time.Millisecond))
+}
+
+// rangePage of a stream messages read with XRANGE, up to the end ID (zero for the end of the stream).
+func (m *Monitor) rangePage(stream string, start, end StreamID) ([]StreamMessage, error) {
+	stop := "+"
+	if !end.IsZero() {
+		stop = end.String()
+	}
+
+	messages, err := m.Redis.XRangeN(stream, start.String(), stop, exportPage).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	page := make([]StreamMessage, 0, len(messages))
+	for _, mes := range messages {
+		id, err := ParseStreamID(mes.ID)
+		if err != nil {
+			LogWarning(err.Error())
+			continue
+		}
+
+		page = append(page, StreamMessage{ID: id, Content: mes.Values})
+	}
+
+	return page, nil
+}
+
+// export messages read page by page with given format.
+func export(w io.Writer, o ExportOptions, page messagesPage) (int, error) {
+	var out exportWriter
+	switch o.Format {
+	case ExportNDJSON:
+		out = newNDJSONWriter(w, o.Stream)
+	case ExportCSV:
+		fields, err := exportFields(o, page)
+		if err != nil {
+			return 0, err
+		}
+
+		out, err = newCSVWriter(w, fields)
+		if err != nil {
+			return 0, err
+		}
+	case ExportXADD:
+		out = &xaddWriter{w: bufio.NewWriter(w), stream: o.Stream}
+	default:
+		return 0, fmt.Errorf("unknown export format %q, expected one of: %s", o.Format, strings.Join(ExportFormats, ", "))
+	}
+
+	n := 0
+	err := eachMessage(o, page, func(m StreamMessage) error {
+		n++
+		return out.write(m)
+	})
+	if err != nil {
+		return n, err
+	}
+
+	return n, out.flush()
+}
+
+// exportFields collects sorted names of all fields of exported messages.
+func exportFields(o ExportOptions, page messagesPage) ([]string, error) {
+	seen := make(map[string]bool)
+	err := eachMessage(o, page, func(m StreamMessage) error {
+		for k := range m.Content {
+			seen[k] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(seen))
+	for k := range seen {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// eachMessage that is exported, reading them page by page from the From ID.
+func eachMessage(o ExportOptions, page messagesPage, fn func(m StreamMessage) error) error {
+	start := o.From
+	for {
+		messages, err := page(start)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range messages {
+			if o.Filter != nil && !o.Filter.Match(o.Stream, m) {
+				continue
+			}
+
+			if err := fn(m); err != nil {
+				return err
+			}
+		}
+
+		if len(messages) < exportPage {
+			return nil
+		}
+
+		start = messages[len(messages)-1].ID.Next()
+	}
+}
+
+// ndjsonWriter writes messages as JSON documents, one per line.
+type ndjsonWriter struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+	stream  string
+}
+
+func newNDJSONWriter(w io.Writer, stream string) *ndjsonWriter {
+	buf := bufio.NewWriter(w)
+
+	return &ndjsonWriter{w: buf, encoder: json.NewEncoder(buf), stream: stream}
+}
+
+func (n *ndjsonWriter) write(m StreamMessage) error {
+	return n.encoder.Encode(newMessageDocument(n.stream, m))
+}
+
+func (n *ndjsonWriter) flush() error {
+	return n.w.Flush()
+}
+
+// csvWriter writes messages as CSV rows with ID and fields columns, missing fields are left empty.
+type csvWriter struct {
+	w      *csv.Writer
+	fields []string
+}
+
+func newCSVWriter(w io.Writer, fields []string) (*csvWriter, error) {
+	c := &csvWriter{w: csv.NewWriter(w), fields: fields}
+	if err := c.w.Write(append([]string{"id"}, fields...)); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *csvWriter) write(m StreamMessage) error {
+	record := make([]string, 0, len(c.fields)+1)
+	record = append(record, m.ID.String())
+	for _, f := range c.fields {
+		v, ok := m.Content[f]
+		if !ok {
+			record = append(record, "")
+			continue
+		}
+
+		record = append(record, fmt.Sprint(v))
+	}
+
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) flush() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// xaddWriter writes messages as XADD commands of redis-cli script, with fields sorted by name.
+type xaddWriter struct {
+	w      *bufio.Writer
+	stream string
+}
+
+func (x *xaddWriter) write(m StreamMessage) error {
+	p := Republication(x.stream, m)
+	p.ID = m.ID
+	_, err := fmt.Fprintln(x.w, p.Command())
+
+	return err
+}
+
+func (x *xaddWriter) flush() error {
+	return x.w.Flush()
+}
+
+// redisQuote string as redis-cli argument in double quotes, escaping special and non printable bytes.
+func redisQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			_, _ = fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
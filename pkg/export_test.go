@@ -0,0 +1,145 @@
+package pkg
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+// memoryPage returns messages page reader of given messages, sorted by ID.
+func memoryPage(messages []StreamMessage) messagesPage {
+	return func(start StreamID) ([]StreamMessage, error) {
+		var page []StreamMessage
+		for _, m := range messages {
+			if !m.ID.Less(start) && len(page) < exportPage {
+				page = append(page, m)
+			}
+		}
+
+		return page, nil
+	}
+}
+
+func Test_export(t *testing.T) {
+	messages := []StreamMessage{
+		{ID: StreamID{Ms: 1}, Content: map[string]interface{}{"name": "created", "id": "5"}},
+		{ID: StreamID{Ms: 2}, Content: map[string]interface{}{"name": "say \"hi\"\n", "extra": "x"}},
+		{ID: StreamID{Ms: 3}, Content: map[string]interface{}{"name": "deleted"}},
+	}
+
+	filter, err := ParseFilter("name!=deleted", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{"ndjson", ExportNDJSON, `{"stream":"events","id":"1-0","fields":{"id":"5","name":"created"}}
+{"stream":"events","id":"2-0","fields":{"extra":"x","name":"say \"hi\"\n"}}
+`},
+		{"csv with union of fields", ExportCSV, `id,extra,id,name
+1-0,,5,created
+2-0,x,,"say ""hi""
+"
+`},
+		{"xadd", ExportXADD, `XADD "events" 1-0 "id" "5" "name" "created"
+XADD "events" 2-0 "extra" "x" "name" "say \"hi\"\n"
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			n, err := export(&b, ExportOptions{Stream: "events", Format: tt.format, Filter: filter}, memoryPage(messages))
+			if err != nil {
+				t.Fatalf("export() error = %v", err)
+			}
+			if n != 2 {
+				t.Errorf("export() n = %d, want 2", n)
+			}
+			if b.String() != tt.want {
+				t.Errorf("export() wrote\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := export(&bytes.Buffer{}, ExportOptions{Format: "xml"}, memoryPage(messages)); err == nil {
+		t.Error("export() with unknown format error = nil, want error")
+	}
+}
+
+func Test_export_binary(t *testing.T) {
+	messages := []StreamMessage{{ID: StreamID{Ms: 1}, Content: map[string]interface{}{"data": "\x1f\x8b\xff", "name": "ł"}}}
+
+	var b bytes.Buffer
+	if _, err := export(&b, ExportOptions{Stream: "events", Format: ExportNDJSON}, memoryPage(messages)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"stream":"events","id":"1-0","fields":{"data":{"base64":"H4v/"},"name":"ł"}}
+`
+	if b.String() != want {
+		t.Errorf("export() wrote %s, want %s", b.String(), want)
+	}
+}
+
+func Test_export_pages(t *testing.T) {
+	var messages []StreamMessage
+	for i := 1; i <= exportPage*2+1; i++ {
+		messages = append(messages, StreamMessage{ID: StreamID{Ms: uint64(i)}, Content: map[string]interface{}{"n": i}})
+	}
+
+	n, err := export(&bytes.Buffer{}, ExportOptions{Format: ExportNDJSON, From: StreamID{Ms: 2}}, memoryPage(messages))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(messages)-1 {
+		t.Errorf("export() n = %d, want %d", n, len(messages)-1)
+	}
+}
+
+func TestParseRangeBound(t *testing.T) {
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	ms := uint64(date.UnixNano() / int64(time.Millisecond))
+	dayMs := uint64(time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local).UnixNano() / int64(time.Millisecond))
+
+	tests := []struct {
+		value   string
+		end     bool
+		want    StreamID
+		wantErr bool
+	}{
+		{"", false, StreamID{}, false},
+		{"+", true, StreamID{}, false},
+		{"5-1", true, StreamID{Ms: 5, Seq: 1}, false},
+		{"5", false, StreamID{Ms: 5}, false},
+		{"5", true, StreamID{Ms: 5, Seq: math.MaxUint64}, false},
+		{"2020-01-02 03:04:05", false, StreamID{Ms: ms}, false},
+		{"2020-01-02 03:04:05", true, StreamID{Ms: ms + 999, Seq: math.MaxUint64}, false},
+		{date.Format(time.RFC3339), true, StreamID{Ms: ms + 999, Seq: math.MaxUint64}, false},
+		{date.Add(time.Millisecond * 20).Format(time.RFC3339Nano), true, StreamID{Ms: ms + 20, Seq: math.MaxUint64}, false},
+		{"2020-01-02", false, StreamID{Ms: dayMs}, false},
+		{"2020-01-02", true, StreamID{Ms: dayMs + 24*3600*1000 - 1, Seq: math.MaxUint64}, false},
+		{"yesterday", false, StreamID{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRangeBound(tt.value, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRangeBound() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRangeBound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_redisQuote(t *testing.T) {
+	if got, want := redisQuote("a \"b\"\\\x00ł"), `"a \"b\"\\\x00\xc5\x82"`; got != want {
+		t.Errorf("redisQuote() = %s, want %s", got, want)
+	}
+}
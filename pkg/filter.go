@@ -0,0 +1,318 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter operators.
+const (
+	opEquals    = "="
+	opNotEquals = "!="
+	opContains  = "~"
+	opMatches   = "=~"
+)
+
+// Filter matches messages by their content. It is made of terms that all have to match:
+//   field=value    field value equals given one
+//   field!=value   field value is different than given one, or there is no such field
+//   field~value    field value contains given text, ignoring case
+//   field=~regex   field value matches regular expression
+//   text           any field name or value contains given text, ignoring case
+// Field can be a JSON path of a value inside decoded field, like $.data.user.ids[0].
+// Values with spaces have to be put in double quotes.
+type Filter struct {
+	terms    []filterTerm
+	decoders *Decoders
+}
+
+// filterTerm is a single predicate of a filter.
+type filterTerm struct {
+	field string
+	path  []string
+	op    string
+	value string
+	regex *regexp.Regexp
+}
+
+// SearchResult is a message matching filter, with name of its stream.
+type SearchResult struct {
+	Stream  string
+	Message StreamMessage
+}
+
+// ParseFilter query into a filter. Field values are decoded with given decoders before they are matched,
+// so filter applies to what is shown. Decoders can be nil to match raw values.
+func ParseFilter(query string, decoders *Decoders) (*Filter, error) {
+	words, err := splitQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Filter{decoders: decoders}
+	for _, w := range words {
+		t, err := parseTerm(w)
+		if err != nil {
+			return nil, err
+		}
+
+		f.terms = append(f.terms, t)
+	}
+
+	return f, nil
+}
+
+// Match checks if message of a stream matches all filter terms. Empty filter matches all messages.
+func (f *Filter) Match(stream string, m StreamMessage) bool {
+	for _, t := range f.terms {
+		if !f.matchTerm(stream, m, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Search stored messages of given streams, or all streams when none is given, with a filter.
+// Results are sorted by stream name and then from the oldest message.
+func (m *Monitor) Search(f *Filter, streams ...string) []SearchResult {
+	var names []string
+	if len(streams) == 0 {
+		for name := range m.Streams.All() {
+			names = append(names, name)
+		}
+	} else {
+		names = append(names, streams...)
+	}
+
+	sort.Strings(names)
+
+	var results []SearchResult
+	for _, name := range names {
+		stream := m.Streams.Find(name)
+		if stream == nil {
+			continue
+		}
+
+		for _, id := range stream.GetMessagesList() {
+			message, err := stream.GetMessage(id)
+			if err != nil {
+				continue
+			}
+
+			if f.Match(stream.Name, *message) {
+				results = append(results, SearchResult{Stream: stream.Name, Message: *message})
+			}
+		}
+	}
+
+	return results
+}
+
+// matchTerm checks single filter term against message.
+func (f *Filter) matchTerm(stream string, m StreamMessage, t filterTerm) bool {
+	if t.op == "" {
+		if containsFold(m.ID.String(), t.value) {
+			return true
+		}
+
+		for k, v := range m.Content {
+			if containsFold(k, t.value) || containsFold(f.decode(stream, k, v), t.value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	raw, ok := m.Content[t.field]
+	if !ok {
+		return t.op == opNotEquals
+	}
+
+	value := f.decode(stream, t.field, raw)
+	if len(t.path) > 0 {
+		value, ok = jsonPath(value, t.path)
+		if !ok {
+			return t.op == opNotEquals
+		}
+	}
+
+	switch t.op {
+	case opEquals:
+		return value == t.value || fmt.Sprint(raw) == t.value
+	case opNotEquals:
+		return value != t.value && fmt.Sprint(raw) != t.value
+	case opContains:
+		return containsFold(value, t.value)
+	case opMatches:
+		return t.regex.MatchString(value)
+	}
+
+	return false
+}
+
+// decode field value with filter decoders.
+func (f *Filter) decode(stream, field string, value interface{}) string {
+	if f.decoders == nil {
+		return fmt.Sprint(value)
+	}
+
+	return f.decoders.Decode(stream, field, value)
+}
+
+// parseTerm of a filter query.
+func parseTerm(word string) (filterTerm, error) {
+	i := strings.IndexAny(word, "=!~")
+	if i <= 0 {
+		return filterTerm{value: word}, nil
+	}
+
+	t := filterTerm{field: word[:i]}
+	rest := word[i:]
+	for _, op := range []string{opMatches, opNotEquals, opEquals, opContains} {
+		if strings.HasPrefix(rest, op) {
+			t.op, t.value = op, rest[len(op):]
+			break
+		}
+	}
+
+	if t.op == "" {
+		return filterTerm{value: word}, nil
+	}
+
+	if strings.HasPrefix(t.field, "$.") {
+		path, err := parsePath(t.field[2:])
+		if err != nil {
+			return filterTerm{}, err
+		}
+
+		t.field, t.path = path[0], path[1:]
+	}
+
+	if t.op == opMatches {
+		regex, err := regexp.Compile(t.value)
+		if err != nil {
+			return filterTerm{}, fmt.Errorf("invalid filter regular expression %q: %v", t.value, err)
+		}
+
+		t.regex = regex
+	}
+
+	return t, nil
+}
+
+// parsePath of keys and array indexes, like data.users[0].name.
+func parsePath(path string) ([]string, error) {
+	var keys []string
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		var indexes []string
+		if i := strings.Index(part, "["); i >= 0 {
+			key = part[:i]
+			for _, index := range strings.Split(part[i+1:], "[") {
+				index = strings.TrimSuffix(index, "]")
+				if _, err := strconv.Atoi(index); err != nil {
+					return nil, fmt.Errorf("invalid filter path %q", path)
+				}
+
+				indexes = append(indexes, index)
+			}
+		}
+
+		if key != "" {
+			keys = append(keys, key)
+		}
+		keys = append(keys, indexes...)
+	}
+
+	if len(keys) == 0 || keys[0] == "" {
+		return nil, fmt.Errorf("invalid filter path %q", path)
+	}
+
+	return keys, nil
+}
+
+// jsonPath finds value under given keys of a JSON document, formatted as text.
+// Strings are returned as they are, other values as JSON.
+func jsonPath(document string, path []string) (string, bool) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[key]; !ok {
+				return "", false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", false
+			}
+			value = v[i]
+		default:
+			return "", false
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+
+	return string(b), true
+}
+
+// splitQuery into words separated by spaces, keeping double quoted text together.
+func splitQuery(query string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	quoted, inWord := false, false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case unicode.IsSpace(r) && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unclosed quote in filter %q", query)
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// containsFold checks if text contains given substring, ignoring case.
+func containsFold(text, substr string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(substr))
+}
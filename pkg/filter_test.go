@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestFilter_Match(t *testing.T) {
+	message := StreamMessage{
+		ID: StreamID{Ms: 1600000000000, Seq: 1},
+		Content: map[string]interface{}{
+			"name":    "user.created",
+			"data":    `{"user":{"id":5,"roles":["admin","editor"]},"note":"Hello World"}`,
+			"payload": base64.StdEncoding.EncodeToString([]byte(`{"amount":10}`)),
+		},
+	}
+
+	decoders, err := NewDecoders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"empty filter", "", true},
+		{"equality", "name=user.created", true},
+		{"equality mismatch", "name=user.deleted", false},
+		{"not equal", "name!=user.deleted", true},
+		{"not equal missing field", "missing!=x", true},
+		{"substring ignores case", "name~CREATED", true},
+		{"regex", "name=~^user\\.(created|deleted)$", true},
+		{"regex mismatch", "name=~^order", false},
+		{"json path", "$.data.user.id=5", true},
+		{"json path array", "$.data.user.roles[1]=editor", true},
+		{"json path missing", "$.data.user.email~a", false},
+		{"json path of decoded value", "$.payload.amount=10", true},
+		{"quoted value", `$.data.note="Hello World"`, true},
+		{"any field", "hello", true},
+		{"message id", "1600000000000-1", true},
+		{"all terms have to match", "name~user hello missing", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseFilter(tt.query, decoders)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if got := f.Match("events", message); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_errors(t *testing.T) {
+	for _, query := range []string{`name="unclosed`, "name=~(", "$.=x", "$.data[a]=x"} {
+		if _, err := ParseFilter(query, nil); err == nil {
+			t.Errorf("ParseFilter(%q) error = nil, want error", query)
+		}
+	}
+}
+
+func Test_parsePath(t *testing.T) {
+	got, err := parsePath("data.users[0][1].name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"data", "users", "0", "1", "name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePath() = %v, want %v", got, want)
+	}
+}
+
+func TestMonitor_Search(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	for _, name := range []string{"orders", "events"} {
+		s := &Stream{Name: name}
+		s.AddMessage(StreamID{Ms: 1}, map[string]interface{}{"name": "created"})
+		s.AddMessage(StreamID{Ms: 2}, map[string]interface{}{"name": "deleted"})
+		m.Streams.Push(s)
+	}
+
+	f, err := ParseFilter("name=created", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, r := range m.Search(f) {
+		got = append(got, r.Stream+"/"+r.Message.ID.String())
+	}
+	want := []string{"events/1-0", "orders/1-0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Search() = %v, want %v", got, want)
+	}
+
+	if got := m.Search(f, "orders", "missing"); len(got) != 1 || got[0].Stream != "orders" {
+		t.Errorf("Search(orders) = %v, want one orders message", got)
+	}
+}
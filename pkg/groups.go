@@ -0,0 +1,186 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsumerGroup holds data returned by XINFO GROUPS command, with consumers of the group.
+type ConsumerGroup struct {
+	// Name of the group
+	Name string
+	// Pending is a number of messages delivered to the group consumers but not acknowledged yet
+	Pending int64
+	// LastDeliveredID is the ID of the last message delivered to the group
+	LastDeliveredID StreamID
+	// Lag is a number of messages not delivered to the group yet, -1 when it is not known.
+	// Before Redis 7.0 it is estimated from stored messages.
+	Lag int64
+	// Consumers of the group
+	Consumers []Consumer
+}
+
+// Consumer holds data returned by XINFO CONSUMERS command.
+type Consumer struct {
+	// Name of the consumer
+	Name string
+	// Pending is a number of messages delivered to the consumer but not acknowledged yet
+	Pending int64
+	// Idle is a time since the consumer last interacted with the stream
+	Idle time.Duration
+}
+
+// collectGroups periodically reads consumer groups of all streams,
+// storing them in streams and notifying about the change.
+func (m *Monitor) collectGroups() {
+	ticker := time.NewTicker(m.GroupsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, s := range m.Streams.All() {
+				if s.Deleted() || m.ctx.Err() != nil {
+					continue
+				}
+
+				groups, err := m.ConsumerGroups(s.Name)
+				if err != nil {
+					if err != ErrNoStream {
+						LogWarning(err.Error())
+					}
+					continue
+				}
+
+				estimateLag(s, groups)
+				if s.setGroups(groups) {
+					m.emitGroupsUpdated(s)
+				}
+			}
+		}
+	}
+}
+
+// ConsumerGroups returns XINFO GROUPS data of a stream with consumers of each group.
+// Returns ErrNoStream when stream key does not exist.
+func (m *Monitor) ConsumerGroups(name string) ([]ConsumerGroup, error) {
+	res, err := m.Redis.Do("XINFO", "GROUPS", name).Result()
+	if err != nil {
+		return nil, infoError(err)
+	}
+
+	groups, err := parseGroups(res)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, g := range groups {
+		groups[i].Consumers, err = m.Consumers(name, g.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, nil
+}
+
+// hasGroup checks if a stream has consumer group with given name. Missing stream has no groups.
+func (m *Monitor) hasGroup(stream, group string) (bool, error) {
+	res, err := m.Redis.Do("XINFO", "GROUPS", stream).Result()
+	if err != nil {
+		if err = infoError(err); err == ErrNoStream {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	groups, err := parseGroups(res)
+	if err != nil {
+		return false, err
+	}
+
+	for _, g := range groups {
+		if g.Name == group {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Consumers returns XINFO CONSUMERS data of a stream consumer group.
+func (m *Monitor) Consumers(stream, group string) ([]Consumer, error) {
+	res, err := m.Redis.Do("XINFO", "CONSUMERS", stream, group).Result()
+	if err != nil {
+		return nil, infoError(err)
+	}
+
+	return parseConsumers(res)
+}
+
+// parseGroups from XINFO GROUPS reply, which is a list of groups fields.
+func parseGroups(res interface{}) ([]ConsumerGroup, error) {
+	replies, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected XINFO GROUPS reply: %v", res)
+	}
+
+	var groups []ConsumerGroup
+	for _, r := range replies {
+		fields, ok := infoFields(r)
+		if !ok {
+			return nil, fmt.Errorf("unexpected XINFO GROUPS reply: %v", res)
+		}
+
+		group := ConsumerGroup{Lag: -1}
+		group.Name, _ = fields["name"].(string)
+		group.Pending, _ = fields["pending"].(int64)
+		if lag, ok := fields["lag"].(int64); ok {
+			group.Lag = lag
+		}
+
+		id, _ := fields["last-delivered-id"].(string)
+		group.LastDeliveredID, _ = ParseStreamID(id)
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// parseConsumers from XINFO CONSUMERS reply, which is a list of consumers fields.
+func parseConsumers(res interface{}) ([]Consumer, error) {
+	replies, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected XINFO CONSUMERS reply: %v", res)
+	}
+
+	var consumers []Consumer
+	for _, r := range replies {
+		fields, ok := infoFields(r)
+		if !ok {
+			return nil, fmt.Errorf("unexpected XINFO CONSUMERS reply: %v", res)
+		}
+
+		consumer := Consumer{}
+		consumer.Name, _ = fields["name"].(string)
+		consumer.Pending, _ = fields["pending"].(int64)
+		idle, _ := fields["idle"].(int64)
+		consumer.Idle = time.Millisecond * time.Duration(idle)
+		consumers = append(consumers, consumer)
+	}
+
+	return consumers, nil
+}
+
+// OnGroupsUpdated assigns handlers that should be invoked when consumer groups of a stream change.
+func (m *Monitor) OnGroupsUpdated(handler func(stream *Stream)) {
+	m.groupsHandlers = append(m.groupsHandlers, handler)
+}
+
+func (m *Monitor) emitGroupsUpdated(stream *Stream) {
+	for _, l := range m.groupsHandlers {
+		l(stream)
+	}
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   interface{}
+		want    []ConsumerGroup
+		wantErr bool
+	}{
+		{"groups with lag",
+			[]interface{}{
+				[]interface{}{"name", "mygroup", "consumers", int64(2), "pending", int64(3), "last-delivered-id", "5-1", "entries-read", int64(7), "lag", int64(4)},
+			},
+			[]ConsumerGroup{{Name: "mygroup", Pending: 3, LastDeliveredID: StreamID{Ms: 5, Seq: 1}, Lag: 4}},
+			false,
+		},
+		{"groups without lag",
+			[]interface{}{
+				[]interface{}{"name", "a", "consumers", int64(0), "pending", int64(0), "last-delivered-id", "0-0"},
+				[]interface{}{"name", "b", "consumers", int64(1), "pending", int64(1), "last-delivered-id", "2-0", "lag", nil},
+			},
+			[]ConsumerGroup{
+				{Name: "a", Lag: -1},
+				{Name: "b", Pending: 1, LastDeliveredID: StreamID{Ms: 2}, Lag: -1},
+			},
+			false,
+		},
+		{"no groups", []interface{}{}, nil, false},
+		{"unexpected reply", "OK", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGroups(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseGroups() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGroups() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseConsumers(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{"name", "alice", "pending", int64(2), "idle", int64(1500)},
+		[]interface{}{"name", "bob", "pending", int64(0), "idle", int64(10), "inactive", int64(10)},
+	}
+	want := []Consumer{
+		{Name: "alice", Pending: 2, Idle: time.Millisecond * 1500},
+		{Name: "bob", Idle: time.Millisecond * 10},
+	}
+
+	got, err := parseConsumers(reply)
+	if err != nil {
+		t.Fatalf("parseConsumers() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseConsumers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrNoStream is returned when requested stream key does not exist in Redis.
+var ErrNoStream = errors.New("stream does not exist")
+
+// StreamInfo holds data returned by XINFO STREAM command.
+type StreamInfo struct {
+	// Length of the stream (XLEN)
+	Length int64
+	// RadixTreeKeys is a number of keys in the radix tree storing stream messages
+	RadixTreeKeys int64
+	// RadixTreeNodes is a number of nodes in the radix tree storing stream messages
+	RadixTreeNodes int64
+	// Groups is a number of consumer groups of the stream
+	Groups int64
+	// LastGeneratedID is the ID of the last message added to the stream,
+	// even if it was already deleted
+	LastGeneratedID StreamID
+	// FirstEntryID is the ID of the oldest message in the stream
+	FirstEntryID StreamID
+	// LastEntryID is the ID of the newest message in the stream
+	LastEntryID StreamID
+}
+
+// StreamInfo returns XINFO STREAM data of a stream.
+// Returns ErrNoStream when stream key does not exist.
+func (m *Monitor) StreamInfo(name string) (*StreamInfo, error) {
+	res, err := m.Redis.Do("XINFO", "STREAM", name).Result()
+	if err != nil {
+		return nil, infoError(err)
+	}
+
+	fields, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected XINFO STREAM reply: %v", res)
+	}
+
+	info := &StreamInfo{}
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, _ := fields[i].(string)
+		value := fields[i+1]
+		switch key {
+		case "length":
+			info.Length, _ = value.(int64)
+		case "radix-tree-keys":
+			info.RadixTreeKeys, _ = value.(int64)
+		case "radix-tree-nodes":
+			info.RadixTreeNodes, _ = value.(int64)
+		case "groups":
+			info.Groups, _ = value.(int64)
+		case "last-generated-id":
+			id, _ := value.(string)
+			info.LastGeneratedID, _ = ParseStreamID(id)
+		case "first-entry":
+			info.FirstEntryID = entryID(value)
+		case "last-entry":
+			info.LastEntryID = entryID(value)
+		}
+	}
+
+	return info, nil
+}
+
+// StreamTTL returns time to live of a stream key, zero when it does not expire.
+// Returns ErrNoStream when stream key does not exist.
+func (m *Monitor) StreamTTL(name string) (time.Duration, error) {
+	ttl, err := m.Redis.PTTL(name).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// Redis replies with -2 for missing keys and -1 for keys without expiry.
+	switch {
+	case ttl == -2*time.Millisecond:
+		return 0, ErrNoStream
+	case ttl < 0:
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+// entryID extracts ID from XINFO entry reply, which is an [ID, [field, value...]] pair.
+// Returns zero ID when there is no entry.
+func entryID(value interface{}) StreamID {
+	entry, ok := value.([]interface{})
+	if !ok || len(entry) == 0 {
+		return StreamID{}
+	}
+
+	s, _ := entry[0].(string)
+	id, _ := ParseStreamID(s)
+
+	return id
+}
+
+// infoError translates XINFO error of missing key, or key holding another type, to ErrNoStream.
+func infoError(err error) error {
+	if strings.Contains(err.Error(), "no such key") || strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		return ErrNoStream
+	}
+
+	return err
+}
+
+// infoFields converts XINFO reply of [key, value...] pairs to a map.
+func infoFields(reply interface{}) (map[string]interface{}, bool) {
+	pairs, ok := reply.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	fields := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key, _ := pairs[i].(string)
+		fields[key] = pairs[i+1]
+	}
+
+	return fields, true
+}
@@ -0,0 +1,137 @@
+package pkg
+
+import (
+	"time"
+)
+
+// checkLifecycle periodically compares tracked streams with XINFO STREAM data
+// to detect streams that were deleted, recreated or trimmed.
+func (m *Monitor) checkLifecycle() {
+	ticker := time.NewTicker(m.LifecycleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			if m.DeletedRetention > 0 {
+				m.forgetDeleted(time.Now().Add(-m.DeletedRetention))
+			}
+
+			for _, s := range m.Streams.All() {
+				m.checkStream(s)
+			}
+		}
+	}
+}
+
+// checkStream compares stream first entry, length and last generated ID
+// with the messages that are stored, evicting the ones no longer in Redis.
+// Last stored ID is taken before XINFO, so messages read in the meantime are not mistaken for recreation.
+func (m *Monitor) checkStream(stream *Stream) {
+	last := stream.LastID()
+	info, err := m.StreamInfo(stream.Name)
+	if err == ErrNoStream {
+		m.deleteStream(stream)
+		return
+	}
+
+	if err != nil {
+		LogWarning(err.Error())
+		return
+	}
+
+	if stream.Deleted() {
+		m.reviveStream(stream)
+		return
+	}
+
+	if last.IsZero() {
+		return
+	}
+
+	if info.LastGeneratedID.Less(last) {
+		stream.EvictAll()
+		m.tracked.setLastID(stream.Name, StreamID{})
+		m.emitStreamRecreated(stream)
+		return
+	}
+
+	var evicted []StreamID
+	if info.Length == 0 {
+		evicted = stream.EvictAll()
+	} else if stream.FirstID().Less(info.FirstEntryID) {
+		evicted = stream.EvictBefore(info.FirstEntryID)
+	}
+
+	if len(evicted) > 0 {
+		m.emitStreamTrimmed(stream, evicted)
+	}
+}
+
+// deleteStream marks stream as deleted, evicting all its messages.
+func (m *Monitor) deleteStream(stream *Stream) {
+	if !stream.setDeleted(true) {
+		return
+	}
+
+	m.tracked.setLastID(stream.Name, StreamID{})
+	m.emitStreamRemoved(stream)
+}
+
+// forgetDeleted streams found deleted before given time, removing them from Streams and reader loops.
+func (m *Monitor) forgetDeleted(before time.Time) {
+	for name, s := range m.Streams.All() {
+		if !s.deletedBefore(before) || m.Streams.Remove(name) == nil {
+			continue
+		}
+
+		m.tracked.untrack(name)
+		m.emitStreamForgotten(s)
+	}
+}
+
+// reviveStream that was deleted before and was created again.
+func (m *Monitor) reviveStream(stream *Stream) {
+	if !stream.setDeleted(false) {
+		return
+	}
+
+	m.emitStreamRecreated(stream)
+}
+
+// OnStreamRecreated assigns handlers that should be invoked when stream was deleted
+// or its IDs were reset and it is written to again. All previous messages are evicted by then.
+func (m *Monitor) OnStreamRecreated(handler func(stream *Stream)) {
+	m.recreatedHandlers = append(m.recreatedHandlers, handler)
+}
+
+// OnStreamTrimmed assigns handlers that should be invoked when stream messages are evicted
+// because they were trimmed in Redis (XTRIM or XADD with MAXLEN).
+func (m *Monitor) OnStreamTrimmed(handler func(stream *Stream, evicted []StreamID)) {
+	m.trimmedHandlers = append(m.trimmedHandlers, handler)
+}
+
+// OnStreamForgotten assigns handlers that should be invoked when deleted stream is removed from Streams
+// after DeletedRetention. It is added again as a new stream when it is created later.
+func (m *Monitor) OnStreamForgotten(handler func(stream *Stream)) {
+	m.forgottenHandlers = append(m.forgottenHandlers, handler)
+}
+
+func (m *Monitor) emitStreamRecreated(stream *Stream) {
+	for _, l := range m.recreatedHandlers {
+		l(stream)
+	}
+}
+
+func (m *Monitor) emitStreamTrimmed(stream *Stream, evicted []StreamID) {
+	for _, l := range m.trimmedHandlers {
+		l(stream, evicted)
+	}
+}
+
+func (m *Monitor) emitStreamForgotten(stream *Stream) {
+	for _, l := range m.forgottenHandlers {
+		l(stream)
+	}
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonitor_forgetDeleted(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	for _, name := range []string{"live", "deleted"} {
+		s := &Stream{Name: name}
+		m.Streams.Push(s)
+		m.tracked.track(s, StreamID{}, 1)
+	}
+	m.Streams.Find("deleted").setDeleted(true)
+
+	var forgotten []string
+	m.OnStreamForgotten(func(stream *Stream) {
+		forgotten = append(forgotten, stream.Name)
+	})
+
+	m.forgetDeleted(time.Now().Add(-time.Minute))
+	if len(forgotten) != 0 {
+		t.Errorf("forgetDeleted() forgot %v before retention", forgotten)
+	}
+
+	m.forgetDeleted(time.Now().Add(time.Second))
+	if len(forgotten) != 1 || forgotten[0] != "deleted" {
+		t.Errorf("forgetDeleted() forgot %v, want [deleted]", forgotten)
+	}
+
+	if m.Streams.Find("deleted") != nil || m.tracked.get("deleted") != nil {
+		t.Errorf("deleted stream is still stored or tracked")
+	}
+
+	if m.Streams.Find("live") == nil || m.tracked.get("live") == nil {
+		t.Errorf("live stream is not stored or tracked")
+	}
+}
+
+func TestMonitor_checkStream(t *testing.T) {
+	c := testRedis(t)
+	m := NewMonitor(c)
+
+	var recreated, removed []string
+	m.OnStreamRecreated(func(stream *Stream) { recreated = append(recreated, stream.Name) })
+	m.OnStreamRemoved(func(stream *Stream) { removed = append(removed, stream.Name) })
+
+	add := func(name string) (*Stream, StreamID) {
+		xadd(t, c, name, "1")
+		messages, err := c.XRange(name, "-", "+").Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, _ := ParseStreamID(messages[0].ID)
+		s := &Stream{Name: name}
+		s.AddMessage(id, messages[0].Values)
+
+		return s, id
+	}
+
+	evicted := func(s *Stream, id StreamID) bool {
+		m, err := s.GetMessage(id)
+		return err != nil || m.Evicted
+	}
+
+	unchanged, id := add("unchanged")
+	m.checkStream(unchanged)
+	if unchanged.Deleted() || evicted(unchanged, id) {
+		t.Errorf("checkStream() changed unchanged stream")
+	}
+
+	missing, id := add("missing")
+	c.Del("missing")
+	m.checkStream(missing)
+	if !missing.Deleted() || !evicted(missing, id) {
+		t.Errorf("checkStream() did not mark missing stream as deleted")
+	}
+
+	recreate, id := add("recreated")
+	recreate.AddMessage(StreamID{Ms: 1 << 50}, map[string]interface{}{"v": "older generation"})
+	m.checkStream(recreate)
+	if !evicted(recreate, id) {
+		t.Errorf("checkStream() kept messages of recreated stream")
+	}
+
+	if len(recreated) != 1 || recreated[0] != "recreated" || len(removed) != 1 || removed[0] != "missing" {
+		t.Errorf("checkStream() recreated %v and removed %v streams", recreated, removed)
+	}
+}
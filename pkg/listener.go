@@ -1,19 +1,44 @@
 package pkg
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
-// Listener struct
+// Delays between restarts of streamer:listen command, growing while it keeps exiting.
+const (
+	listenRetryMin = time.Second
+	listenRetryMax = time.Minute
+)
+
+// Listener struct. It is safe for concurrent use.
 type Listener struct {
-	Items                   map[string]*StreamListener
+	// Group is a consumer group name of all stream listeners, unique per instance by default
+	Group string
+	// Consumer is a consumer name of all stream listeners, unique per instance by default
+	Consumer string
+	// Copy makes listeners read copies of streams instead of the streams, which Monitor keeps,
+	// see Monitor.CopyStreams. Streams are read directly when it is false or Monitor is nil.
+	Copy bool
+	// Monitor finds consumer groups created by listeners, so Cleanup destroys them, and copies streams.
+	// Cleanup does nothing when nil.
+	Monitor                 *Monitor
+	mu                      sync.Mutex
+	items                   map[string]*StreamListener
+	created                 map[string]bool
+	handlers                map[string][]string
 	newListenerHandlers     []func(listener StreamListener)
 	listenerChangedHandlers []func(listener StreamListener, lastOutput string)
 	artisan                 *Artisan
+	ctx                     context.Context
+	cancel                  context.CancelFunc
+	wg                      sync.WaitGroup
 }
 
 // NewListener creates listener with artisan command.
@@ -25,30 +50,125 @@ func NewListener() (*Listener, error) {
 		return nil, errors.New("artisan not detected")
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	listener := &Listener{
-		artisan: artisan,
+		Group:    InstanceName(),
+		Consumer: InstanceName(),
+		artisan:  artisan,
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 
 	return listener, nil
 }
 
-// StartListening on all streams that streamer:list command yields out.
-func (l *Listener) StartListening() {
+// Start listening in background on all streams that streamer:list command yields out.
+// Listener works until Stop is called or given context is done.
+func (l *Listener) Start(ctx context.Context) {
+	l.ctx, l.cancel = context.WithCancel(ctx)
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.loadHandlers()
+		l.startListening()
+	}()
+}
+
+// loadHandlers of events from streamer:list command.
+func (l *Listener) loadHandlers() {
+	output, _, err := l.artisan.Exec("streamer:list")
+	if err != nil {
+		LogWarning(fmt.Sprintf("Failed to list events listeners: %v", err))
+		return
+	}
+
+	handlers := parseStreamerList(string(output))
+	l.mu.Lock()
+	l.handlers = handlers
+	l.mu.Unlock()
+}
+
+// Handlers returns local listeners of an event, as listed by streamer:list command.
+// Returns false when listeners of events are not known.
+func (l *Listener) Handlers(event string) ([]string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.handlers == nil {
+		return nil, false
+	}
+
+	handlers := make([]string, len(l.handlers[event]))
+	copy(handlers, l.handlers[event])
+
+	return handlers, true
+}
+
+// Stop listening, killing all artisan commands and waiting for them to exit.
+func (l *Listener) Stop() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+// Cleanup destroys consumer groups created by listeners with XGROUP DESTROY,
+// and deletes copies of streams in copy mode. Call it after Stop.
+func (l *Listener) Cleanup() {
+	l.mu.Lock()
+	created := l.created
+	l.created = nil
+	l.mu.Unlock()
+
+	for stream := range created {
+		if err := l.Monitor.DestroyGroup(stream, l.Group); err != nil {
+			LogWarning(fmt.Sprintf("Failed to destroy %s group of %s stream: %v", l.Group, stream, err))
+		}
+	}
+
+	if l.copying() {
+		l.Monitor.DeleteCopies()
+	}
+}
+
+// copying checks if listeners read copies of streams kept by Monitor.
+func (l *Listener) copying() bool {
+	return l.Copy && l.Monitor != nil
+}
+
+// InstanceName is a consumer group and consumer name unique for running swarm instance.
+func InstanceName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("swarm-%s-%d", host, os.Getpid())
+}
+
+// startListening on all streams that streamer:list command yields out.
+func (l *Listener) startListening() {
 	args := []string{"streamer:list", "--compact"}
-	cmd, err := l.artisan.ExecPipe(func(output string, cmd *exec.Cmd) error {
+	cmd, err := l.artisan.ExecPipe(l.ctx, func(output string, cmd *exec.Cmd) error {
 		streams := strings.Fields(output)
 		for _, s := range streams {
 			if s == "Event" {
 				continue
 			}
 
-			go l.Listen(Stream{Name: s})
+			stream := &Stream{Name: s}
+			l.wg.Add(1)
+			go func() {
+				defer l.wg.Done()
+				l.Listen(stream)
+			}()
 		}
 
 		return nil
 	}, args...)
 
 	if err != nil {
+		if l.ctx.Err() == nil {
+			LogWarning(fmt.Sprintf("Failed to list streams: %v", err))
+		}
 		return
 	}
 
@@ -59,71 +179,142 @@ func (l *Listener) StartListening() {
 }
 
 // Listen starts listening via Artisan command call and adds output to the stack.
-// Restarts command listening when it returns error code 1.
-func (l *Listener) Listen(stream Stream) {
-	lis := l.AddStreamListener(stream.Name)
-	if lis.stopped {
+// Restarts command listening when it exits, with growing delays, until Listener is stopped.
+// New consumer group starts after the last known message of the stream, so its history is not handled again.
+func (l *Listener) Listen(stream *Stream) {
+	name := stream.Name
+	if l.copying() {
+		name = l.Monitor.CopyName(stream.Name)
+	}
+	lis := l.AddStreamListener(name)
+	if l.isStopped(lis) {
 		return
 	}
 
-	var out string
-	messages := stream.GetMessagesList()
-	lastID := "0-0"
-	if len(messages) > 0 {
-		lastID = messages[len(messages)-1]
+	if l.copying() {
+		l.Monitor.CopyStream(stream.Name)
 	}
 
-	id := fmt.Sprintf("--last_id=%s", lastID)
-	args := []string{"streamer:listen", stream.Name, "--group=monitor", "--consumer=monitor", id}
+	args := l.listenArgs(name)
+	if l.newGroup(name) {
+		args = l.listenArgs(name, "--last_id="+l.startID(stream))
+	}
 
-	for {
-		cmd, err := l.artisan.ExecPipe(func(output string, cmd *exec.Cmd) error {
-			if lis.stopped {
+	var out string
+	retry := newBackoff(listenRetryMin, listenRetryMax)
+	for l.ctx.Err() == nil {
+		started := time.Now()
+		cmd, err := l.artisan.ExecPipe(l.ctx, func(output string, cmd *exec.Cmd) error {
+			if l.isStopped(lis) {
 				return errors.New("stopped")
 			}
 
 			out = fmt.Sprintf("%s: %s", time.Now().Format("01-02-2006 15:04:05"), output)
+			l.mu.Lock()
 			lis.Output = append(lis.Output, out)
+			l.mu.Unlock()
 			if lis.HasNoListeners(output) {
-				lis.stopped = true
-				l.emitListenerChanged(*lis, out)
+				l.emitListenerChanged(l.update(lis, func() { lis.stopped = true }), out)
 				return errors.New("stopped")
 			}
 
 			if lis.IsFailing(output) {
-				lis.warning = true
-				l.emitListenerChanged(*lis, out)
+				l.emitListenerChanged(l.update(lis, func() { lis.warning = true }), out)
 			}
 
 			return nil
 		}, args...)
 
-		if lis.stopped {
+		if l.isStopped(lis) || l.ctx.Err() != nil {
 			return
 		}
 
 		if err != nil {
+			LogWarning(fmt.Sprintf("Failed to listen on %s stream: %v", name, err))
+			l.emitListenerChanged(l.update(lis, func() { lis.error = true }), err.Error())
 			return
 		}
 
-		code := cmd.ProcessState.ExitCode()
-		if code == 1 {
-			lis.error = true
-			l.emitListenerChanged(*lis, out)
+		if cmd.ProcessState.ExitCode() == 1 {
+			l.emitListenerChanged(l.update(lis, func() { lis.error = true }), out)
 			LogWarning(out)
-			args = []string{"streamer:listen", stream.Name, "--group=monitor", "--consumer=monitor"}
-			continue
 		}
+
+		if time.Since(started) > listenRetryMax {
+			retry.Reset()
+		}
+
+		args = l.listenArgs(name)
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-time.After(retry.Fail()):
+		}
+	}
+}
+
+// listenArgs of streamer:listen command reading a stream with Listener group and consumer.
+func (l *Listener) listenArgs(stream string, options ...string) []string {
+	args := []string{"streamer:listen", stream, "--group=" + l.Group, "--consumer=" + l.Consumer}
+
+	return append(args, options...)
+}
+
+// startID of a new consumer group, which is the last known message ID of the stream,
+// or "$" for the last message in Redis when no message is known yet.
+// Copies of streams keep IDs of the original messages.
+func (l *Listener) startID(stream *Stream) string {
+	if id := stream.LastID(); !id.IsZero() {
+		return id.String()
+	}
+
+	if l.Monitor != nil {
+		if s := l.Monitor.Streams.Find(stream.Name); s != nil && !s.LastID().IsZero() {
+			return s.LastID().String()
+		}
+	}
+
+	return "$"
+}
+
+// newGroup checks if Listener group does not exist on a stream yet, remembering it is created by the listener.
+// Without Monitor the group is assumed to be new, as its name is unique by default.
+func (l *Listener) newGroup(stream string) bool {
+	if l.Monitor == nil {
+		return true
+	}
+
+	exists, err := l.Monitor.hasGroup(stream, l.Group)
+	if err != nil {
+		LogWarning(fmt.Sprintf("Failed to check %s group of %s stream: %v", l.Group, stream, err))
+		return false
+	}
+
+	if exists {
+		return false
+	}
+
+	l.mu.Lock()
+	if l.created == nil {
+		l.created = make(map[string]bool)
 	}
+	l.created[stream] = true
+	l.mu.Unlock()
+
+	return true
 }
 
+// AddStreamListener for a stream, unless there already is one.
+// Returned StreamListener must be changed only with Listener lock held.
 func (l *Listener) AddStreamListener(name string) *StreamListener {
-	if l.Items == nil {
-		l.Items = make(map[string]*StreamListener)
+	l.mu.Lock()
+	if l.items == nil {
+		l.items = make(map[string]*StreamListener)
 	}
 
-	lis, ok := l.Items[name]
+	lis, ok := l.items[name]
 	if ok {
+		l.mu.Unlock()
 		return lis
 	}
 
@@ -132,12 +323,58 @@ func (l *Listener) AddStreamListener(name string) *StreamListener {
 		Output: nil,
 	}
 
-	l.Items[name] = lis
-	l.emitNewListener(*lis)
+	l.items[name] = lis
+	snapshot := lis.snapshot()
+	l.mu.Unlock()
+
+	l.emitNewListener(snapshot)
 
 	return lis
 }
 
+// Find returns a copy of stream listener by stream name.
+func (l *Listener) Find(name string) (StreamListener, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lis, ok := l.items[name]
+	if !ok {
+		return StreamListener{}, false
+	}
+
+	return lis.snapshot(), true
+}
+
+// Items returns copies of all stream listeners.
+func (l *Listener) Items() []StreamListener {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var items []StreamListener
+	for _, lis := range l.items {
+		items = append(items, lis.snapshot())
+	}
+
+	return items
+}
+
+// update stream listener with Listener lock held, returning its copy.
+func (l *Listener) update(lis *StreamListener, change func()) StreamListener {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change()
+
+	return lis.snapshot()
+}
+
+func (l *Listener) isStopped(lis *StreamListener) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return lis.stopped
+}
+
 func (l *Listener) OnNewListener(handle func(listener StreamListener)) {
 	l.newListenerHandlers = append(l.newListenerHandlers, handle)
 }
@@ -166,6 +403,15 @@ type StreamListener struct {
 	error   bool
 }
 
+// snapshot returns a copy of stream listener that does not share its output.
+func (s *StreamListener) snapshot() StreamListener {
+	c := *s
+	c.Output = make([]string, len(s.Output))
+	copy(c.Output, s.Output)
+
+	return c
+}
+
 func (s StreamListener) ParseOutput() string {
 	var content string
 	for _, i := range s.Output {
@@ -183,17 +429,39 @@ func (s StreamListener) IsFailing(output string) bool {
 	return strings.Contains(output, "Listener error. Failed processing message")
 }
 
-// Status of StreamListener as a formatted string.
-func (s StreamListener) Status() string {
+// StreamListener states returned by State.
+const (
+	ListenerOK      = "ok"
+	ListenerWarning = "warning"
+	ListenerError   = "error"
+	ListenerStopped = "stopped"
+)
+
+// State of StreamListener, one of ListenerOK, ListenerWarning, ListenerError or ListenerStopped.
+func (s StreamListener) State() string {
 	if s.error {
-		return "[red]WARNING[red]"
+		return ListenerError
 	}
 
 	if s.warning {
-		return "[yellow]WARNING[yellow]"
+		return ListenerWarning
 	}
 
 	if s.stopped {
+		return ListenerStopped
+	}
+
+	return ListenerOK
+}
+
+// Status of StreamListener as a formatted string.
+func (s StreamListener) Status() string {
+	switch s.State() {
+	case ListenerError:
+		return "[red]WARNING[red]"
+	case ListenerWarning:
+		return "[yellow]WARNING[yellow]"
+	case ListenerStopped:
 		return "[grey]STOPPED[grey]"
 	}
 
@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestListener_ConcurrentAccess(t *testing.T) {
+	l := &Listener{}
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			lis := l.AddStreamListener(fmt.Sprintf("stream-%d", i%10))
+			l.update(lis, func() {
+				lis.Output = append(lis.Output, "output")
+				lis.warning = true
+			})
+		}
+		done <- true
+	}()
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			for _, lis := range l.Items() {
+				_ = lis.ParseOutput()
+				_ = lis.Status()
+			}
+			if lis, ok := l.Find("stream-1"); ok {
+				_ = lis.ParseOutput()
+			}
+		}
+		done <- true
+	}()
+
+	<-done
+	<-done
+
+	if got := len(l.Items()); got != 10 {
+		t.Errorf("len(Items()) = %v, want %v", got, 10)
+	}
+}
+
+func TestStreamListener_State(t *testing.T) {
+	tests := []struct {
+		name     string
+		listener StreamListener
+		want     string
+	}{
+		{"ok", StreamListener{}, ListenerOK},
+		{"warning", StreamListener{warning: true}, ListenerWarning},
+		{"error wins over warning", StreamListener{warning: true, error: true}, ListenerError},
+		{"stopped", StreamListener{stopped: true}, ListenerStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.listener.State(); got != tt.want {
+				t.Errorf("State() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListener_listenArgs(t *testing.T) {
+	l := &Listener{Group: "swarm-a", Consumer: "swarm-b"}
+	want := []string{"streamer:listen", "swarm.orders", "--group=swarm-a", "--consumer=swarm-b", "--last_id=$"}
+	if got := l.listenArgs("swarm.orders", "--last_id=$"); !reflect.DeepEqual(got, want) {
+		t.Errorf("listenArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestListener_startID(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	known := &Stream{Name: "known"}
+	known.AddMessage(StreamID{Ms: 5}, map[string]interface{}{"a": "b"})
+	m.Streams.Push(known)
+
+	tests := []struct {
+		name    string
+		monitor *Monitor
+		stream  *Stream
+		want    string
+	}{
+		{"unknown stream", m, &Stream{Name: "orders"}, "$"},
+		{"stream known by monitor", m, &Stream{Name: "known"}, "5-0"},
+		{"without monitor", nil, &Stream{Name: "known"}, "$"},
+		{"stream with messages", nil, known, "5-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Listener{Monitor: tt.monitor}
+			if got := l.startID(tt.stream); got != tt.want {
+				t.Errorf("startID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
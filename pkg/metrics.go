@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"time"
+)
+
+const (
+	// rateBucket is a time span of messages counted together.
+	rateBucket = time.Second * 10
+	// rateBuckets cover the longest rate window of 15 minutes.
+	rateBuckets = int64(time.Minute * 15 / rateBucket)
+)
+
+// StreamMetrics of stream throughput.
+type StreamMetrics struct {
+	// Rate1m is an average number of messages per second in the last minute
+	Rate1m float64
+	// Rate5m is an average number of messages per second in the last 5 minutes
+	Rate5m float64
+	// Rate15m is an average number of messages per second in the last 15 minutes
+	Rate15m float64
+	// LastMessage is a time when the newest stored message was added, zero when there are no messages
+	LastMessage time.Time
+	// PerMinute is a number of messages added in each of the last 15 minutes, from the oldest minute
+	PerMinute []int64
+}
+
+// Metrics of stream throughput at given time. Messages are counted by the time in their IDs.
+func (s *Stream) Metrics(now time.Time) StreamMetrics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metrics := StreamMetrics{
+		Rate1m:    s.rate.rate(now, time.Minute),
+		Rate5m:    s.rate.rate(now, time.Minute*5),
+		Rate15m:   s.rate.rate(now, time.Minute*15),
+		PerMinute: s.rate.perMinute(now),
+	}
+
+	if order := s.order; len(order) > 0 {
+		metrics.LastMessage = order[len(order)-1].Time()
+	}
+
+	return metrics
+}
+
+// MessagesAfter counts stored messages newer than given ID, which were not evicted.
+// Returns false when stream may have messages newer than given ID that are not stored.
+func (s *Stream) MessagesAfter(id StreamID) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	complete := false
+	for _, i := range s.order {
+		if s.messages[i].Evicted {
+			continue
+		}
+
+		if !id.Less(i) {
+			complete = true
+			continue
+		}
+
+		count++
+	}
+
+	return count, complete
+}
+
+// StreamMetrics returns throughput metrics of a stream, false when the stream is unknown.
+func (m *Monitor) StreamMetrics(name string) (StreamMetrics, bool) {
+	stream := m.Streams.Find(name)
+	if stream == nil {
+		return StreamMetrics{}, false
+	}
+
+	return stream.Metrics(time.Now()), true
+}
+
+// estimateLag of groups that Redis does not report lag for (before 7.0),
+// counting stored messages newer than the last delivered one.
+func estimateLag(stream *Stream, groups []ConsumerGroup) {
+	for i, g := range groups {
+		if g.Lag >= 0 {
+			continue
+		}
+
+		if lag, ok := stream.MessagesAfter(g.LastDeliveredID); ok {
+			groups[i].Lag = lag
+		}
+	}
+}
+
+// rateCounter counts messages in time buckets covering the last 15 minutes.
+type rateCounter struct {
+	buckets [rateBuckets]int64
+	// last is a number of the newest bucket since Unix epoch
+	last int64
+}
+
+// add message added at given time. Messages from the future, e.g. with IDs given explicitly
+// or generated on a server with skewed clock, are counted at current time.
+func (r *rateCounter) add(t, now time.Time) {
+	if t.After(now) {
+		t = now
+	}
+
+	b := t.UnixNano() / int64(rateBucket)
+	r.advance(b)
+	if b <= r.last-rateBuckets {
+		return
+	}
+
+	r.buckets[b%rateBuckets]++
+}
+
+// advance newest bucket to given one, clearing buckets that went out of range.
+func (r *rateCounter) advance(b int64) {
+	if b <= r.last {
+		return
+	}
+
+	from := r.last + 1
+	if b-from >= rateBuckets {
+		from = b - rateBuckets + 1
+	}
+
+	for i := from; i <= b; i++ {
+		r.buckets[i%rateBuckets] = 0
+	}
+
+	r.last = b
+}
+
+// count messages added in given number of buckets up to the one of given time.
+func (r *rateCounter) count(now time.Time, buckets int64) int64 {
+	b := now.UnixNano() / int64(rateBucket)
+	r.advance(b)
+
+	var count int64
+	for i := b - buckets + 1; i <= b && i <= r.last; i++ {
+		if i > r.last-rateBuckets && i >= 0 {
+			count += r.buckets[i%rateBuckets]
+		}
+	}
+
+	return count
+}
+
+// rate of messages per second in given window up to given time.
+func (r *rateCounter) rate(now time.Time, window time.Duration) float64 {
+	return float64(r.count(now, int64(window/rateBucket))) / window.Seconds()
+}
+
+// perMinute counts messages added in each of the last 15 minutes up to given time, from the oldest minute.
+func (r *rateCounter) perMinute(now time.Time) []int64 {
+	perMinute := int64(time.Minute / rateBucket)
+	counts := make([]int64, rateBuckets/perMinute)
+	for i := range counts {
+		end := now.Add(-time.Minute * time.Duration(len(counts)-1-i))
+		counts[i] = r.count(end, perMinute)
+	}
+
+	return counts
+}
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStream_Metrics(t *testing.T) {
+	now := time.Unix(100000, 0)
+	s := &Stream{Name: "Stream"}
+	for _, ago := range []time.Duration{time.Second * 5, time.Second * 30, time.Minute * 3, time.Minute * 10, time.Minute * 20} {
+		id := StreamID{Ms: uint64(now.Add(-ago).UnixNano() / int64(time.Millisecond))}
+		s.AddMessage(id, map[string]interface{}{"foo": "bar"})
+	}
+	s.AddMessage(StreamID{Ms: uint64(now.Add(-time.Second*5).UnixNano() / int64(time.Millisecond))}, map[string]interface{}{"foo": "baz"})
+
+	got := s.Metrics(now)
+	if got.Rate1m != 2.0/60 {
+		t.Errorf("Rate1m = %v, want %v", got.Rate1m, 2.0/60)
+	}
+	if got.Rate5m != 3.0/300 {
+		t.Errorf("Rate5m = %v, want %v", got.Rate5m, 3.0/300)
+	}
+	if got.Rate15m != 4.0/900 {
+		t.Errorf("Rate15m = %v, want %v", got.Rate15m, 4.0/900)
+	}
+	if want := now.Add(-time.Second * 5); !got.LastMessage.Equal(want) {
+		t.Errorf("LastMessage = %v, want %v", got.LastMessage, want)
+	}
+
+	want := make([]int64, 15)
+	want[14], want[11], want[4] = 2, 1, 1
+	if !reflect.DeepEqual(got.PerMinute, want) {
+		t.Errorf("PerMinute = %v, want %v", got.PerMinute, want)
+	}
+
+	if got := s.Metrics(now.Add(time.Hour)); got.Rate15m != 0 {
+		t.Errorf("Rate15m after an hour = %v, want 0", got.Rate15m)
+	}
+}
+
+func TestStream_MetricsFutureID(t *testing.T) {
+	now := time.Now()
+	s := &Stream{Name: "Stream"}
+	s.AddMessage(StreamID{Ms: uint64(now.Add(time.Hour).UnixNano() / int64(time.Millisecond))}, map[string]interface{}{"foo": "bar"})
+	s.AddMessage(StreamID{Ms: uint64(now.UnixNano() / int64(time.Millisecond))}, map[string]interface{}{"foo": "bar"})
+
+	if got := s.Metrics(time.Now()); got.Rate1m != 2.0/60 {
+		t.Errorf("Rate1m = %v, want %v", got.Rate1m, 2.0/60)
+	}
+}
+
+func TestStream_MessagesAfter(t *testing.T) {
+	s := &Stream{Name: "Stream"}
+	for i := 1; i <= 5; i++ {
+		s.AddMessage(StreamID{Ms: uint64(i)}, map[string]interface{}{"foo": "bar"})
+	}
+	s.EvictBefore(StreamID{Ms: 2})
+
+	tests := []struct {
+		name         string
+		id           StreamID
+		want         int64
+		wantComplete bool
+	}{
+		{"counts newer messages", StreamID{Ms: 3}, 2, true},
+		{"nothing newer", StreamID{Ms: 5}, 0, true},
+		{"older than stored messages", StreamID{Ms: 1}, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, complete := s.MessagesAfter(tt.id)
+			if got != tt.want || complete != tt.wantComplete {
+				t.Errorf("MessagesAfter() = %v, %v, want %v, %v", got, complete, tt.want, tt.wantComplete)
+			}
+		})
+	}
+}
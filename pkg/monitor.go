@@ -1,119 +1,394 @@
 package pkg
 
 import (
+	"context"
 	"github.com/go-redis/redis"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Monitor connects with Redis and reads streams and messages from it
 type Monitor struct {
-	Redis           *redis.Client
-	Streams         *Streams
-	streamHandlers  []func(stream Stream)
-	messageHandlers []func(stream Stream, message StreamMessage)
+	Redis   *redis.Client
+	Streams *Streams
+	// Discovery mode of new streams, either DiscoveryPolling or DiscoveryNotifications.
+	Discovery string
+	// ScanCount is a COUNT hint passed to each SCAN call.
+	ScanCount int64
+	// ScanInterval is a delay between consecutive SCAN calls.
+	ScanInterval time.Duration
+	// ScanCacheSize limits how many non-stream keys are remembered
+	// when SCAN TYPE option is not available.
+	ScanCacheSize int
+	// MessagesLimit of the most recent messages kept in memory per stream, zero means no limit.
+	MessagesLimit int
+	// MemoryBudget in bytes for messages of all streams, zero means no limit.
+	// When exceeded, the oldest messages of the biggest streams are dropped.
+	MemoryBudget int64
+	// Readers is a number of loops reading new messages with multi-key XREAD calls.
+	Readers int
+	// ReadBlock is the longest time a single XREAD call blocks waiting for messages.
+	ReadBlock time.Duration
+	// RetryMin is the first delay before retrying failed reads, doubled with each failure up to RetryMax.
+	RetryMin time.Duration
+	// RetryMax is the longest delay before retrying failed reads.
+	RetryMax time.Duration
+	// LifecycleInterval is a delay between checks of streams being deleted, recreated or trimmed.
+	LifecycleInterval time.Duration
+	// DeletedRetention is how long deleted streams are kept before they are forgotten, zero keeps them forever.
+	DeletedRetention time.Duration
+	// GroupsInterval is a delay between collections of consumer groups of all streams.
+	GroupsInterval time.Duration
+	// Decoders of message fields values matched by watches, raw values are matched when nil.
+	Decoders *Decoders
+	// WatchCommand is a shell command run for every watch match, with message JSON on its standard input.
+	// At most watchCommandsLimit commands run at once, others wait for them.
+	WatchCommand string
+	// ReadOnly disables all writes to Redis, they fail with ErrReadOnly.
+	ReadOnly bool
+	// Audit log of destructive commands, they are not recorded when nil.
+	Audit             *AuditLog
+	scanCursor        uint64
+	noTypeFilter      bool
+	checkedKeys       *keyCache
+	tracked           tracker
+	ctx               context.Context
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
+	streamHandlers    []func(stream *Stream)
+	messageHandlers   []func(stream *Stream, message StreamMessage)
+	removedHandlers   []func(stream *Stream)
+	renamedHandlers   []func(stream *Stream, oldName string)
+	recreatedHandlers []func(stream *Stream)
+	forgottenHandlers []func(stream *Stream)
+	trimmedHandlers   []func(stream *Stream, evicted []StreamID)
+	resyncHandlers    []func(missed time.Duration)
+	groupsHandlers    []func(stream *Stream)
+	fallbackHandlers  []func(err error)
+	matchHandlers     []func(match WatchMatch)
+	watchMu           sync.Mutex
+	watches           []*Watch
+	matches           []WatchMatch
+	watchSlots        chan struct{}
+	copyPrefix        string
+	copyMu            sync.Mutex
+	copied            map[string]bool
 }
 
 // NewMonitor creates monitor struct for usage.
 func NewMonitor(c *redis.Client) *Monitor {
 	return &Monitor{
-		Redis:   c,
-		Streams: &Streams{},
+		Redis:             c,
+		Streams:           &Streams{},
+		Discovery:         DiscoveryPolling,
+		ScanCount:         1000,
+		ScanInterval:      time.Millisecond * 250,
+		ScanCacheSize:     10000,
+		LifecycleInterval: time.Second * 5,
+		DeletedRetention:  time.Minute * 5,
+		GroupsInterval:    time.Second * 5,
+		MessagesLimit:     1000,
+		Readers:           2,
+		ReadBlock:         time.Second,
+		RetryMin:          time.Millisecond * 100,
+		RetryMax:          time.Second * 30,
 	}
 }
 
-// StartMonitoring uses Redis MONITOR command to catch all incoming streams
-// and starts listening on them, adding them to Streams collection.
-func (m *Monitor) StartMonitoring() {
-	//var errorsCount int
-	checkedKeys := make(map[string]string)
-	tick := time.NewTicker(time.Second * 1).C
+// Start discovering streams and reading their messages in background, adding them to Streams collection.
+// Monitor works until Stop is called or given context is done.
+func (m *Monitor) Start(ctx context.Context) {
+	m.ctx, m.cancel = context.WithCancel(ctx)
+	m.checkedKeys = newKeyCache(m.ScanCacheSize)
+	if m.Readers < 1 {
+		m.Readers = 1
+	}
+
+	m.run(m.checkLifecycle)
+	m.run(m.collectGroups)
+	for i := 0; i < m.Readers; i++ {
+		reader := i
+		m.run(func() {
+			m.readLoop(reader)
+		})
+	}
+	m.run(m.discover)
+}
+
+// Stop monitoring and wait for all background work to finish.
+func (m *Monitor) Stop() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	m.wg.Wait()
+}
+
+// run function in background, Stop waits for it to return.
+func (m *Monitor) run(f func()) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		f()
+	}()
+}
+
+// sleep for given time. Returns false when monitor was stopped in the meantime.
+func (m *Monitor) sleep(d time.Duration) bool {
+	select {
+	case <-m.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// discover streams. With DiscoveryNotifications mode it follows Redis keyspace notifications,
+// falling back to polling when they are not enabled.
+func (m *Monitor) discover() {
+	if m.Discovery == DiscoveryNotifications {
+		m.followNotifications()
+		if m.ctx.Err() != nil {
+			return
+		}
+	}
+
+	m.poll()
+}
+
+// poll uses Redis SCAN command to incrementally discover streams.
+// Every tick continues the scan from the cursor where previous one stopped.
+func (m *Monitor) poll() {
+	ticker := time.NewTicker(m.ScanInterval)
+	defer ticker.Stop()
 	for {
 		select {
-		case <-tick:
-			keys, err := m.Redis.Keys("*").Result()
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			keys, err := m.scanStreams()
 			if err != nil {
 				LogError(err.Error())
 				continue
 			}
 
 			for _, k := range keys {
-				if _, ok := checkedKeys[k]; ok {
-					continue
-				}
-
-				t, err := m.Redis.Type(k).Result()
-				if err != nil {
-					LogError(err.Error())
-					continue
-				}
-				checkedKeys[k] = t
+				m.addStream(k)
 			}
+		}
+	}
+}
 
-			for k, t := range checkedKeys {
-				found := m.Streams.Find(k)
-				if found != nil || t != "stream" {
-					continue
-				}
+// addStream to Streams collection and start reading its messages, unless it is already there.
+// Copies of streams are not added.
+func (m *Monitor) addStream(name string) {
+	if m.isCopy(name) {
+		return
+	}
 
-				stream := &Stream{Name: k}
-				m.Streams.Push(stream)
-				m.emitStreamAdded(*stream)
-				go m.readEvents(stream)
-			}
+	if found := m.Streams.Find(name); found != nil {
+		m.reviveStream(found)
+		return
+	}
+
+	stream := &Stream{Name: name, Limit: m.MessagesLimit}
+	m.Streams.Push(stream)
+	m.emitStreamAdded(stream)
+	m.run(func() {
+		m.loadHistory(stream)
+	})
+}
+
+// removeStream marks stream as deleted if it is in Streams collection.
+func (m *Monitor) removeStream(name string) {
+	stream := m.Streams.Find(name)
+	if stream == nil {
+		return
+	}
+
+	m.deleteStream(stream)
+}
+
+// renameStream in Streams collection. When old stream is not known,
+// the new key is checked and added as any other discovered stream.
+func (m *Monitor) renameStream(old, new string) {
+	m.removeStream(new)
+	stream := m.Streams.Rename(old, new)
+	if stream == nil {
+		if t, err := m.Redis.Type(new).Result(); err == nil && t == "stream" {
+			m.addStream(new)
 		}
+
+		return
 	}
+
+	m.tracked.rename(old, stream)
+	m.emitStreamRenamed(stream, old)
 }
 
-func (m *Monitor) readEvents(stream *Stream) {
-	messages, err := m.Redis.XRange(stream.Name, "-", "+").Result()
+// scanStreams runs single SCAN call starting at last known cursor
+// and returns stream keys found in that batch.
+// SCAN TYPE option is used when Redis supports it (6.0+), otherwise
+// each unknown key type is checked separately and non-stream keys are cached.
+func (m *Monitor) scanStreams() ([]string, error) {
+	if !m.noTypeFilter {
+		cmd := redis.NewScanCmd(m.Redis.Process, "scan", m.scanCursor, "count", m.ScanCount, "type", "stream")
+		_ = m.Redis.Process(cmd)
+		keys, cursor, err := cmd.Result()
+		if err == nil {
+			m.scanCursor = cursor
+			return keys, nil
+		}
+
+		if !strings.HasPrefix(err.Error(), "ERR") {
+			return nil, err
+		}
+
+		LogDebug("SCAN TYPE option not supported, falling back to TYPE checks")
+		m.noTypeFilter = true
+	}
+
+	keys, cursor, err := m.Redis.Scan(m.scanCursor, "", m.ScanCount).Result()
 	if err != nil {
-		LogWarning(err.Error())
+		return nil, err
 	}
+	m.scanCursor = cursor
 
-	for _, mes := range messages {
-		_ = mes
-		newMess := stream.AddMessage(mes.ID, mes.Values)
-		m.emitMessageAdded(*stream, newMess)
+	var streams []string
+	for _, k := range keys {
+		if m.checkedKeys.Has(k) || m.Streams.Find(k) != nil {
+			continue
+		}
+
+		t, err := m.Redis.Type(k).Result()
+		if err != nil {
+			LogError(err.Error())
+			continue
+		}
+
+		if t != "stream" {
+			m.checkedKeys.Add(k)
+			continue
+		}
+
+		streams = append(streams, k)
 	}
 
-	for {
-		newMessages, err := m.Redis.XRead(&redis.XReadArgs{
-			Streams: []string{stream.Name, "$"},
-			Block:   0,
-		}).Result()
+	return streams, nil
+}
+
+// LoadOlder fetches up to count messages older than the oldest one kept in stream
+// and adds them to it. Returns number of loaded messages.
+func (m *Monitor) LoadOlder(stream *Stream, count int64) (int, error) {
+	first := stream.FirstID()
+	if first.IsZero() {
+		return 0, nil
+	}
 
+	messages, err := m.Redis.XRevRangeN(stream.Name, first.Prev().String(), "-", count).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, mes := range messages {
+		id, err := ParseStreamID(mes.ID)
 		if err != nil {
 			LogWarning(err.Error())
+			continue
 		}
 
-		for _, xStream := range newMessages {
-			_ = xStream.Stream
-			for _, mes := range xStream.Messages {
-				newMess := stream.AddMessage(mes.ID, mes.Values)
-				m.emitMessageAdded(*stream, newMess)
+		stream.AddOlderMessage(id, mes.Values)
+	}
+	m.enforceBudget()
+
+	return len(messages), nil
+}
+
+// enforceBudget drops the oldest messages of the biggest streams
+// until all streams fit in MemoryBudget.
+func (m *Monitor) enforceBudget() {
+	if m.MemoryBudget <= 0 {
+		return
+	}
+
+	for {
+		var total int64
+		var biggest *Stream
+		for _, s := range m.Streams.All() {
+			total += s.Size()
+			if biggest == nil || s.Size() > biggest.Size() {
+				biggest = s
 			}
 		}
+
+		if total <= m.MemoryBudget || biggest == nil {
+			return
+		}
+
+		n := biggest.MessagesCount() / 10
+		if n < 1 {
+			n = 1
+		}
+
+		if biggest.DropOldest(n) == 0 {
+			return
+		}
 	}
 }
 
 // OnNewStream assigns handlers that should be invoked when Monitor catches new stream by
-func (m *Monitor) OnNewStream(handler func(stream Stream)) {
+func (m *Monitor) OnNewStream(handler func(stream *Stream)) {
 	m.streamHandlers = append(m.streamHandlers, handler)
 }
 
 // OnNewMessage assigns handlers that should be invoked when Monitor reads new message from a Stream
-func (m *Monitor) OnNewMessage(handler func(stream Stream, message StreamMessage)) {
+func (m *Monitor) OnNewMessage(handler func(stream *Stream, message StreamMessage)) {
 	m.messageHandlers = append(m.messageHandlers, handler)
 }
 
-func (m *Monitor) emitStreamAdded(stream Stream) {
+// OnStreamRemoved assigns handlers that should be invoked when stream is deleted from Redis or expires.
+func (m *Monitor) OnStreamRemoved(handler func(stream *Stream)) {
+	m.removedHandlers = append(m.removedHandlers, handler)
+}
+
+// OnStreamRenamed assigns handlers that should be invoked when stream gets renamed.
+func (m *Monitor) OnStreamRenamed(handler func(stream *Stream, oldName string)) {
+	m.renamedHandlers = append(m.renamedHandlers, handler)
+}
+
+// OnResync assigns handlers that should be invoked when reading streams succeeds after failures,
+// with the time reading was failing. Messages added in that time are read after resync.
+func (m *Monitor) OnResync(handler func(missed time.Duration)) {
+	m.resyncHandlers = append(m.resyncHandlers, handler)
+}
+
+func (m *Monitor) emitStreamAdded(stream *Stream) {
 	for _, l := range m.streamHandlers {
 		l(stream)
 	}
 }
 
-func (m *Monitor) emitMessageAdded(stream Stream, message StreamMessage) {
+func (m *Monitor) emitMessageAdded(stream *Stream, message StreamMessage) {
 	for _, l := range m.messageHandlers {
 		l(stream, message)
 	}
 }
+
+func (m *Monitor) emitStreamRemoved(stream *Stream) {
+	for _, l := range m.removedHandlers {
+		l(stream)
+	}
+}
+
+func (m *Monitor) emitStreamRenamed(stream *Stream, oldName string) {
+	for _, l := range m.renamedHandlers {
+		l(stream, oldName)
+	}
+}
+
+func (m *Monitor) emitResync(missed time.Duration) {
+	for _, l := range m.resyncHandlers {
+		l(missed)
+	}
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"context"
+	"github.com/go-redis/redis"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// testRedis returns client of Redis server given in SWARM_TEST_REDIS, with its database 15 flushed.
+// Test is skipped when the server is not given.
+func testRedis(t *testing.T) *redis.Client {
+	addr := os.Getenv("SWARM_TEST_REDIS")
+	if addr == "" {
+		t.Skip("SWARM_TEST_REDIS is not set")
+	}
+
+	c := redis.NewClient(&redis.Options{Addr: addr, DB: 15, ReadTimeout: -1})
+	if err := c.FlushDB().Err(); err != nil {
+		t.Fatalf("failed to flush test Redis, err: %v", err)
+	}
+
+	return c
+}
+
+// testMonitor started on test Redis with short intervals, it has to be stopped by the test.
+func testMonitor(t *testing.T, c *redis.Client, setup func(m *Monitor)) *Monitor {
+	m := NewMonitor(c)
+	m.ScanInterval = time.Millisecond * 10
+	m.LifecycleInterval = time.Millisecond * 50
+	m.GroupsInterval = time.Millisecond * 50
+	m.ReadBlock = time.Millisecond * 50
+	m.RetryMax = time.Millisecond * 200
+	if setup != nil {
+		setup(m)
+	}
+
+	m.Start(context.Background())
+
+	return m
+}
+
+// eventually checks condition until it is met, failing the test after a few seconds.
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 5)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+// xadd message with a single field to a stream of test Redis.
+func xadd(t *testing.T, c *redis.Client, stream string, value string) {
+	t.Helper()
+	if err := c.XAdd(&redis.XAddArgs{Stream: stream, Values: map[string]interface{}{"v": value}}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMonitor_StartStop(t *testing.T) {
+	c := testRedis(t)
+	for _, name := range []string{"a", "b"} {
+		xadd(t, c, name, "1")
+	}
+
+	before := runtime.NumGoroutine()
+	m := testMonitor(t, c, nil)
+	eventually(t, "streams", func() bool { return m.Streams.Find("a") != nil && m.Streams.Find("b") != nil })
+
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Stop() did not return")
+	}
+
+	eventually(t, "monitor goroutines to exit", func() bool { return runtime.NumGoroutine() <= before })
+}
@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DiscoveryPolling finds streams by periodically scanning Redis keys.
+	DiscoveryPolling = "polling"
+	// DiscoveryNotifications finds streams with Redis keyspace notifications.
+	DiscoveryNotifications = "notifications"
+)
+
+// keyevents that Monitor subscribes to in notifications discovery mode.
+var keyevents = []string{"xadd", "del", "expired", "rename_from", "rename_to"}
+
+// followNotifications keeps Streams collection up to date with keyspace notifications until monitor is stopped.
+// Lost subscription is restored with exponential backoff. Returns when notifications cannot be used at all,
+// so streams are discovered by polling, notifying fallback handlers.
+func (m *Monitor) followNotifications() {
+	retry := newBackoff(m.RetryMin, m.RetryMax)
+	subscribed := false
+	for m.ctx.Err() == nil {
+		started := time.Now()
+		err := m.watchNotifications(func() { subscribed = true })
+		if m.ctx.Err() != nil {
+			return
+		}
+
+		if !subscribed {
+			LogWarning(fmt.Sprintf("Keyspace notifications unavailable, falling back to polling: %v", err))
+			m.emitDiscoveryFallback(err)
+			return
+		}
+
+		if time.Since(started) > m.RetryMax {
+			retry.Reset()
+		}
+
+		delay := retry.Fail()
+		LogWarning(fmt.Sprintf("Keyspace notifications subscription lost, subscribing again in %v: %v", delay, err))
+		m.sleep(delay)
+	}
+}
+
+// OnDiscoveryFallback assigns handlers that should be invoked when keyspace notifications cannot be used
+// and streams are discovered by polling instead.
+func (m *Monitor) OnDiscoveryFallback(handler func(err error)) {
+	m.fallbackHandlers = append(m.fallbackHandlers, handler)
+}
+
+func (m *Monitor) emitDiscoveryFallback(err error) {
+	for _, h := range m.fallbackHandlers {
+		h(err)
+	}
+}
+
+// watchNotifications subscribes to Redis keyevent notifications and keeps
+// Streams collection up to date with streams being added, removed and renamed.
+// Streams existing before subscription are found with a single full scan.
+// Subscribed is invoked once the subscription is confirmed.
+// Returns error when notifications are not enabled, subscription fails or monitor is stopped.
+func (m *Monitor) watchNotifications(subscribed func()) error {
+	config, err := m.Redis.ConfigGet("notify-keyspace-events").Result()
+	if err != nil {
+		return err
+	}
+
+	if len(config) < 2 || !notificationsEnabled(fmt.Sprint(config[1])) {
+		return errors.New("notify-keyspace-events does not include keyevent (E) stream (t) and generic (g) notifications")
+	}
+
+	db := m.Redis.Options().DB
+	var channels []string
+	for _, e := range keyevents {
+		channels = append(channels, fmt.Sprintf("__keyevent@%d__:%s", db, e))
+	}
+
+	pubsub := m.Redis.Subscribe(channels...)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(); err != nil {
+		return err
+	}
+	subscribed()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-m.ctx.Done():
+			_ = pubsub.Close()
+		case <-done:
+		}
+	}()
+
+	for m.ctx.Err() == nil {
+		keys, err := m.scanStreams()
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			m.addStream(k)
+		}
+
+		if m.scanCursor == 0 {
+			break
+		}
+	}
+
+	var renamedFrom string
+	for {
+		msg, err := pubsub.ReceiveMessage()
+		if err != nil {
+			return err
+		}
+
+		key := msg.Payload
+		switch msg.Channel[strings.LastIndex(msg.Channel, ":")+1:] {
+		case "xadd":
+			m.addStream(key)
+		case "del", "expired":
+			m.removeStream(key)
+		case "rename_from":
+			renamedFrom = key
+		case "rename_to":
+			m.renameStream(renamedFrom, key)
+			renamedFrom = ""
+		}
+	}
+}
+
+// notificationsEnabled checks if notify-keyspace-events flags
+// contain everything that is needed to follow streams.
+func notificationsEnabled(flags string) bool {
+	if !strings.Contains(flags, "E") {
+		return false
+	}
+
+	if strings.Contains(flags, "A") {
+		return true
+	}
+
+	return strings.Contains(flags, "g") && strings.Contains(flags, "t")
+}
@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func Test_notificationsEnabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags string
+		want  bool
+	}{
+		{"disabled", "", false},
+		{"all keyevents", "EA", true},
+		{"generic and stream keyevents", "Egtx", true},
+		{"keyspace only", "KA", false},
+		{"missing stream events", "Egx", false},
+		{"missing generic events", "Et", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notificationsEnabled(tt.flags); got != tt.want {
+				t.Errorf("notificationsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_notifications(t *testing.T) {
+	c := testRedis(t)
+	config, err := c.ConfigGet("notify-keyspace-events").Result()
+	if err != nil {
+		t.Skipf("CONFIG is not available, err: %v", err)
+	}
+	defer c.ConfigSet("notify-keyspace-events", fmt.Sprint(config[1]))
+	if err := c.ConfigSet("notify-keyspace-events", "Egt").Err(); err != nil {
+		t.Skipf("keyspace notifications are not available, err: %v", err)
+	}
+
+	xadd(t, c, "existing", "1")
+	m := testMonitor(t, c, func(m *Monitor) {
+		m.Discovery = DiscoveryNotifications
+		m.ScanInterval = time.Hour
+		m.LifecycleInterval = time.Hour
+		m.OnDiscoveryFallback(func(err error) {
+			t.Errorf("discovery fell back to polling, err: %v", err)
+		})
+	})
+	defer m.Stop()
+
+	eventually(t, "existing stream", func() bool { return m.Streams.Find("existing") != nil })
+
+	xadd(t, c, "added", "1")
+	eventually(t, "added stream", func() bool { return m.Streams.Find("added") != nil })
+
+	c.Del("added")
+	eventually(t, "deleted stream", func() bool { return m.Streams.Find("added").Deleted() })
+
+	c.Rename("existing", "renamed")
+	eventually(t, "renamed stream", func() bool { return m.Streams.Find("renamed") != nil && m.Streams.Find("existing") == nil })
+
+	if err := c.ClientKillByFilter("TYPE", "pubsub").Err(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 200)
+	xadd(t, c, "resubscribed", "1")
+	eventually(t, "stream added after subscription was lost", func() bool { return m.Streams.Find("resubscribed") != nil })
+}
+
+func TestMonitor_notificationsFallback(t *testing.T) {
+	c := testRedis(t)
+	if config, err := c.ConfigGet("notify-keyspace-events").Result(); err == nil {
+		defer c.ConfigSet("notify-keyspace-events", fmt.Sprint(config[1]))
+		c.ConfigSet("notify-keyspace-events", "")
+	}
+
+	fallback := make(chan error, 1)
+	m := testMonitor(t, c, func(m *Monitor) {
+		m.Discovery = DiscoveryNotifications
+		m.OnDiscoveryFallback(func(err error) {
+			fallback <- err
+		})
+	})
+	defer m.Stop()
+
+	select {
+	case <-fallback:
+	case <-time.After(time.Second * 5):
+		t.Fatal("discovery did not fall back to polling")
+	}
+
+	xadd(t, c, "polled", "1")
+	eventually(t, "polled stream", func() bool { return m.Streams.Find("polled") != nil })
+}
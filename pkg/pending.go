@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"github.com/go-redis/redis"
+	"time"
+)
+
+// PendingEntry holds data of a message delivered to a consumer group but not acknowledged yet,
+// as returned by XPENDING command.
+type PendingEntry struct {
+	// ID of the pending message
+	ID StreamID
+	// Consumer that owns the message
+	Consumer string
+	// Idle is a time since the message was last delivered
+	Idle time.Duration
+	// Deliveries is a number of times the message was delivered
+	Deliveries int64
+}
+
+// PendingEntries returns up to count oldest pending entries of a stream consumer group.
+func (m *Monitor) PendingEntries(stream, group string, count int64) ([]PendingEntry, error) {
+	res, err := m.Redis.XPendingExt(&redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingEntries(res)
+}
+
+// Claim transfers ownership of pending messages to given consumer with XCLAIM command.
+// Delivery count of the messages is not changed. Returns IDs of claimed messages.
+func (m *Monitor) Claim(stream, group, consumer string, ids ...StreamID) ([]StreamID, error) {
+	if err := m.writable(); err != nil {
+		return nil, err
+	}
+
+	res, err := m.Redis.XClaimJustID(&redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		Messages: idStrings(ids),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []StreamID
+	for _, r := range res {
+		id, err := ParseStreamID(r)
+		if err != nil {
+			return nil, err
+		}
+
+		claimed = append(claimed, id)
+	}
+
+	return claimed, nil
+}
+
+// Ack acknowledges pending messages of a consumer group with XACK command.
+// Returns number of acknowledged messages.
+func (m *Monitor) Ack(stream, group string, ids ...StreamID) (int64, error) {
+	if err := m.writable(); err != nil {
+		return 0, err
+	}
+
+	return m.Redis.XAck(stream, group, idStrings(ids)...).Result()
+}
+
+// pendingEntries converts XPENDING reply to pending entries.
+func pendingEntries(res []redis.XPendingExt) ([]PendingEntry, error) {
+	var entries []PendingEntry
+	for _, r := range res {
+		id, err := ParseStreamID(r.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, PendingEntry{
+			ID:         id,
+			Consumer:   r.Consumer,
+			Idle:       r.Idle,
+			Deliveries: r.RetryCount,
+		})
+	}
+
+	return entries, nil
+}
+
+// idStrings formats IDs the way Redis commands take them.
+func idStrings(ids []StreamID) []string {
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = id.String()
+	}
+
+	return list
+}
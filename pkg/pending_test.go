@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"github.com/go-redis/redis"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_pendingEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     []redis.XPendingExt
+		want    []PendingEntry
+		wantErr bool
+	}{
+		{"converts entries",
+			[]redis.XPendingExt{
+				{Id: "1-0", Consumer: "alice", Idle: time.Second, RetryCount: 1},
+				{Id: "2-5", Consumer: "bob", Idle: time.Minute, RetryCount: 3},
+			},
+			[]PendingEntry{
+				{ID: StreamID{Ms: 1}, Consumer: "alice", Idle: time.Second, Deliveries: 1},
+				{ID: StreamID{Ms: 2, Seq: 5}, Consumer: "bob", Idle: time.Minute, Deliveries: 3},
+			},
+			false,
+		},
+		{"fails on invalid ID", []redis.XPendingExt{{Id: "invalid"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pendingEntries(tt.res)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pendingEntries() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pendingEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrReadOnly is returned by Monitor methods writing to Redis when Monitor is read-only.
+var ErrReadOnly = errors.New("monitor is read-only, writing to Redis is disabled")
+
+// Streamer envelope fields values written by Laravel Streamer.
+const (
+	streamerType    = "event"
+	streamerVersion = "1.3"
+)
+
+// Field of a published message. Fields are published in order they are given.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Publication is a message added to a stream with XADD.
+type Publication struct {
+	Stream string
+	// ID of the message, generated by Redis when zero
+	ID StreamID
+	// MaxLen trims the stream to about this number of messages (MAXLEN ~), no trimming when zero
+	MaxLen int64
+	Fields []Field
+}
+
+// Validate publication before it is sent to Redis.
+func (p Publication) Validate() error {
+	if strings.TrimSpace(p.Stream) == "" {
+		return errors.New("stream name is required")
+	}
+
+	if p.MaxLen < 0 {
+		return fmt.Errorf("invalid MAXLEN %d, it cannot be negative", p.MaxLen)
+	}
+
+	if len(p.Fields) == 0 {
+		return errors.New("message needs at least one field")
+	}
+
+	seen := make(map[string]bool, len(p.Fields))
+	for _, f := range p.Fields {
+		if f.Name == "" {
+			return errors.New("field name cannot be empty")
+		}
+
+		if seen[f.Name] {
+			return fmt.Errorf("duplicated %s field", f.Name)
+		}
+		seen[f.Name] = true
+	}
+
+	return nil
+}
+
+// args of XADD command adding the publication.
+func (p Publication) args() []interface{} {
+	args := []interface{}{"XADD", p.Stream}
+	if p.MaxLen > 0 {
+		args = append(args, "MAXLEN", "~", p.MaxLen)
+	}
+
+	id := "*"
+	if !p.ID.IsZero() {
+		id = p.ID.String()
+	}
+	args = append(args, id)
+
+	for _, f := range p.Fields {
+		args = append(args, f.Name, f.Value)
+	}
+
+	return args
+}
+
+// Publish message to a stream, creating the stream when it does not exist. Returns ID of added message.
+func (m *Monitor) Publish(p Publication) (StreamID, error) {
+	if err := m.writable(); err != nil {
+		return StreamID{}, err
+	}
+
+	if err := p.Validate(); err != nil {
+		return StreamID{}, err
+	}
+
+	reply, err := m.Redis.Do(p.args()...).String()
+	if err != nil {
+		return StreamID{}, err
+	}
+
+	return ParseStreamID(reply)
+}
+
+// writable checks if Monitor can write to Redis.
+func (m *Monitor) writable() error {
+	if m.ReadOnly {
+		return ErrReadOnly
+	}
+
+	return nil
+}
+
+// StreamerEnvelope fields of Laravel Streamer event with JSON data, created at given time.
+func StreamerEnvelope(name, domain, data string, created time.Time) ([]Field, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("event name is required")
+	}
+
+	if !json.Valid([]byte(data)) {
+		return nil, fmt.Errorf("event data is not valid JSON: %s", data)
+	}
+
+	return []Field{
+		{"_id", "*"},
+		{"type", streamerType},
+		{"version", streamerVersion},
+		{"name", name},
+		{"domain", domain},
+		{"created", strconv.FormatInt(created.Unix(), 10)},
+		{"data", data},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPublication_Validate(t *testing.T) {
+	fields := []Field{{"name", "user.created"}}
+	tests := []struct {
+		name    string
+		p       Publication
+		wantErr bool
+	}{
+		{"valid", Publication{Stream: "events", Fields: fields}, false},
+		{"missing stream", Publication{Stream: " ", Fields: fields}, true},
+		{"negative maxlen", Publication{Stream: "events", MaxLen: -1, Fields: fields}, true},
+		{"no fields", Publication{Stream: "events"}, true},
+		{"empty field name", Publication{Stream: "events", Fields: []Field{{"", "x"}}}, true},
+		{"duplicated field", Publication{Stream: "events", Fields: []Field{{"a", "1"}, {"a", "2"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublication_args(t *testing.T) {
+	p := Publication{Stream: "events", ID: StreamID{Ms: 5, Seq: 1}, MaxLen: 100, Fields: []Field{{"b", "2"}, {"a", "1"}}}
+	want := []interface{}{"XADD", "events", "MAXLEN", "~", int64(100), "5-1", "b", "2", "a", "1"}
+	if got := p.args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %v, want %v", got, want)
+	}
+
+	p = Publication{Stream: "events", Fields: []Field{{"a", "1"}}}
+	want = []interface{}{"XADD", "events", "*", "a", "1"}
+	if got := p.args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("args() = %v, want %v", got, want)
+	}
+}
+
+func TestMonitor_Publish_readOnly(t *testing.T) {
+	m := &Monitor{ReadOnly: true}
+	if _, err := m.Publish(Publication{Stream: "events", Fields: []Field{{"a", "1"}}}); err != ErrReadOnly {
+		t.Errorf("Publish() error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestStreamerEnvelope(t *testing.T) {
+	fields, err := StreamerEnvelope("user.created", "app", `{"id":5}`, time.Unix(1600000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := make(map[string]interface{})
+	for _, f := range fields {
+		content[f.Name] = f.Value
+	}
+
+	m := StreamMessage{Content: content}
+	event, ok := m.StreamerEvent()
+	if !ok || event.Name != "user.created" || event.Domain != "app" || !event.Created.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("StreamerEnvelope() = %v, not recognised as Streamer event", fields)
+	}
+
+	if _, err := StreamerEnvelope("user.created", "app", "{", time.Now()); err == nil {
+		t.Error("StreamerEnvelope() with invalid data error = nil, want error")
+	}
+}
@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"io/ioutil"
+)
+
+// ProtobufPrefix of protobuf decoders names, followed by full name of the message type.
+const ProtobufPrefix = "protobuf:"
+
+// LoadProtobuf registers decoder of every message type defined in a compiled FileDescriptorSet file
+// (e.g. made with `protoc --include_imports --descriptor_set_out`), named ProtobufPrefix + full type name.
+func (d *Decoders) LoadProtobuf(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, set); err != nil {
+		return fmt.Errorf("invalid descriptor set %s: %v", path, err)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return fmt.Errorf("invalid descriptor set %s: %v", path, err)
+	}
+
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		d.registerMessages(file.Messages())
+		return true
+	})
+
+	return nil
+}
+
+// registerMessages decoders, including nested message types.
+func (d *Decoders) registerMessages(messages protoreflect.MessageDescriptors) {
+	for i := 0; i < messages.Len(); i++ {
+		desc := messages.Get(i)
+		if desc.IsMapEntry() {
+			continue
+		}
+
+		d.Register(protobufDecoder{desc: desc})
+		d.registerMessages(desc.Messages())
+	}
+}
+
+// protobufDecoder decodes protobuf message of a type into pretty printed JSON.
+type protobufDecoder struct {
+	desc protoreflect.MessageDescriptor
+}
+
+func (p protobufDecoder) Name() string {
+	return ProtobufPrefix + string(p.desc.FullName())
+}
+
+func (p protobufDecoder) Decode(value []byte) ([]byte, error) {
+	message := dynamicpb.NewMessage(p.desc)
+	if err := proto.Unmarshal(value, message); err != nil {
+		return nil, err
+	}
+
+	b, err := protojson.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonDecoder{}.Decode(b)
+}
@@ -0,0 +1,78 @@
+package pkg
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecoders_LoadProtobuf(t *testing.T) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("orders.proto"),
+		Package: proto.String("orders"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Created"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("amount"), JsonName: proto.String("amount"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+			},
+		}},
+	}
+
+	set, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "swarm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "set.pb")
+	if err := ioutil.WriteFile(path, set, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := dynamicpb.NewMessage(fd.Messages().Get(0))
+	message.Set(fd.Messages().Get(0).Fields().ByName("id"), protoreflect.ValueOfString("abc"))
+	message.Set(fd.Messages().Get(0).Fields().ByName("amount"), protoreflect.ValueOfInt32(5))
+	encoded, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.LoadProtobuf(path); err != nil {
+		t.Fatalf("LoadProtobuf() error = %v", err)
+	}
+	if err := d.AddRule(DecoderRule{Stream: "orders", Field: "payload", Decoders: []string{"protobuf:orders.Created"}}); err != nil {
+		t.Fatalf("AddRule() error = %v", err)
+	}
+
+	want := "{\n  \"amount\": 5,\n  \"id\": \"abc\"\n}"
+	if got := d.Decode("orders", "payload", string(encoded)); got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+
+	if got := d.Decode("orders", "payload", "\xff"); !strings.Contains(got, "protobuf:orders.Created decoder failed") {
+		t.Errorf("Decode() = %q, want raw value with decoder error", got)
+	}
+}
@@ -0,0 +1,236 @@
+package pkg
+
+import (
+	"fmt"
+	"github.com/go-redis/redis"
+	"strings"
+	"sync"
+)
+
+// trackedStream is a stream read by one of the reader loops,
+// with ID of the last message that was seen on it, used when stream has no messages stored.
+type trackedStream struct {
+	stream *Stream
+	lastID StreamID
+	reader int
+}
+
+// tracker assigns streams to reader loops and keeps their last seen IDs.
+type tracker struct {
+	mu      sync.Mutex
+	streams map[string]*trackedStream
+	next    int
+}
+
+// track stream by one of n reader loops, reading messages after lastID.
+func (t *tracker) track(stream *Stream, lastID StreamID, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.streams == nil {
+		t.streams = make(map[string]*trackedStream)
+	}
+
+	t.streams[stream.Name] = &trackedStream{stream: stream, lastID: lastID, reader: t.next % n}
+	t.next++
+}
+
+// rename tracked stream to the renamed one, keeping its reader loop and last seen ID.
+func (t *tracker) rename(old string, renamed *Stream) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ts, ok := t.streams[old]
+	if !ok {
+		return
+	}
+
+	delete(t.streams, old)
+	ts.stream = renamed
+	t.streams[renamed.Name] = ts
+}
+
+// untrack stream, so reader loops no longer read it.
+func (t *tracker) untrack(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.streams, name)
+}
+
+// setLastID of a tracked stream, so that next read starts after it.
+func (t *tracker) setLastID(name string, id StreamID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ts, ok := t.streams[name]; ok {
+		ts.lastID = id
+	}
+}
+
+// forReader returns XREAD STREAMS arguments (all keys followed by all IDs)
+// of streams assigned to given reader loop. Reading resumes from the last ID
+// stored in a stream, so no messages are lost after failed reads.
+// Deleted streams are left out, as their keys may be reused by other types.
+func (t *tracker) forReader(reader int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var keys, ids []string
+	for name, ts := range t.streams {
+		if ts.reader != reader || ts.stream.Deleted() {
+			continue
+		}
+
+		id := ts.stream.LastID()
+		if id.IsZero() {
+			id = ts.lastID
+		}
+
+		keys = append(keys, name)
+		ids = append(ids, id.String())
+	}
+
+	return append(keys, ids...)
+}
+
+// get tracked stream by name.
+func (t *tracker) get(name string) *Stream {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ts, ok := t.streams[name]; ok {
+		return ts.stream
+	}
+
+	return nil
+}
+
+// loadHistory of a stream and hand it over to reader loops,
+// which continue reading right after the last loaded message.
+// Failed reads are retried with exponential backoff.
+func (m *Monitor) loadHistory(stream *Stream) {
+	retry := newBackoff(m.RetryMin, m.RetryMax)
+	messages, err := m.readHistory(stream.Name)
+	for err != nil {
+		delay := retry.Fail()
+		LogWarning(fmt.Sprintf("Failed to read %s stream history, retrying in %v: %v", stream.Name, delay, err))
+		if !m.sleep(delay) {
+			return
+		}
+		messages, err = m.readHistory(stream.Name)
+	}
+
+	var lastID StreamID
+	for _, mes := range messages {
+		id, err := ParseStreamID(mes.ID)
+		if err != nil {
+			LogWarning(err.Error())
+			continue
+		}
+
+		lastID = id
+		if _, err := stream.GetMessage(id); err == nil {
+			continue
+		}
+
+		newMess := stream.AddMessage(id, mes.Values)
+		m.emitMessageAdded(stream, newMess)
+	}
+	m.enforceBudget()
+
+	m.tracked.track(stream, lastID, m.Readers)
+}
+
+// readHistory returns the most recent messages of a stream up to MessagesLimit,
+// from the oldest to the newest.
+func (m *Monitor) readHistory(name string) ([]redis.XMessage, error) {
+	if m.MessagesLimit <= 0 {
+		return m.Redis.XRange(name, "-", "+").Result()
+	}
+
+	messages, err := m.Redis.XRevRangeN(name, "+", "-", int64(m.MessagesLimit)).Result()
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, err
+}
+
+// readLoop issues multi-key XREAD calls over all streams assigned to the reader, until monitor is stopped.
+// Failed calls are retried with exponential backoff, when reading succeeds again
+// the time it was failing is reported to resync handlers.
+// Each call blocks for at most ReadBlock, so newly tracked streams join the next round,
+// and reads at most MessagesLimit messages per stream, as older ones would be dropped anyway.
+func (m *Monitor) readLoop(reader int) {
+	retry := newBackoff(m.RetryMin, m.RetryMax)
+	for m.ctx.Err() == nil {
+		streams := m.tracked.forReader(reader)
+		if len(streams) == 0 {
+			m.sleep(m.ReadBlock)
+			continue
+		}
+
+		newMessages, err := m.Redis.XRead(&redis.XReadArgs{
+			Streams: streams,
+			Count:   int64(m.MessagesLimit),
+			Block:   m.ReadBlock,
+		}).Result()
+
+		if err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE") && m.dropWrongType(streams[:len(streams)/2]) {
+			continue
+		}
+
+		if err != nil && err != redis.Nil {
+			delay := retry.Fail()
+			LogWarning(fmt.Sprintf("Failed to read streams, retrying in %v: %v", delay, err))
+			m.sleep(delay)
+			continue
+		}
+
+		if missed := retry.Reset(); missed > 0 {
+			m.emitResync(missed)
+		}
+
+		for _, xStream := range newMessages {
+			stream := m.tracked.get(xStream.Stream)
+			if stream == nil {
+				continue
+			}
+
+			for _, mes := range xStream.Messages {
+				id, err := ParseStreamID(mes.ID)
+				if err != nil {
+					LogWarning(err.Error())
+					continue
+				}
+
+				m.reviveStream(stream)
+				newMess := stream.AddMessage(id, mes.Values)
+				m.tracked.setLastID(xStream.Stream, id)
+				m.emitMessageAdded(stream, newMess)
+				m.checkWatches(stream, newMess)
+			}
+		}
+		m.enforceBudget()
+	}
+}
+
+// dropWrongType marks streams whose keys hold other types as deleted, so they are no longer read.
+// Returns false when all keys are streams.
+func (m *Monitor) dropWrongType(keys []string) bool {
+	dropped := false
+	for _, k := range keys {
+		t, err := m.Redis.Type(k).Result()
+		if err != nil || t == "stream" {
+			continue
+		}
+
+		if stream := m.tracked.get(k); stream != nil {
+			m.deleteStream(stream)
+			dropped = true
+		}
+	}
+
+	return dropped
+}
@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTracker_forReader(t *testing.T) {
+	tr := &tracker{}
+	tr.track(&Stream{Name: "a"}, StreamID{Ms: 1}, 2)
+	tr.track(&Stream{Name: "b"}, StreamID{Ms: 2}, 2)
+	tr.track(&Stream{Name: "c"}, StreamID{Ms: 3}, 2)
+
+	if got := tr.forReader(1); !reflect.DeepEqual(got, []string{"b", "2-0"}) {
+		t.Errorf("forReader(1) = %v, want %v", got, []string{"b", "2-0"})
+	}
+
+	tr.setLastID("b", StreamID{Ms: 5})
+	tr.rename("b", &Stream{Name: "d"})
+	if got := tr.forReader(1); !reflect.DeepEqual(got, []string{"d", "5-0"}) {
+		t.Errorf("forReader(1) = %v, want %v", got, []string{"d", "5-0"})
+	}
+
+	if got := len(tr.forReader(0)); got != 4 {
+		t.Errorf("len(forReader(0)) = %v, want %v", got, 4)
+	}
+
+	tr.get("d").setDeleted(true)
+	if got := tr.forReader(1); len(got) != 0 {
+		t.Errorf("forReader(1) = %v, want deleted stream left out", got)
+	}
+}
+
+func TestMonitor_readLoop(t *testing.T) {
+	c := testRedis(t)
+	for _, name := range []string{"a", "b", "c"} {
+		xadd(t, c, name, "1")
+	}
+
+	m := testMonitor(t, c, func(m *Monitor) {
+		m.Readers = 1
+		m.LifecycleInterval = time.Hour
+	})
+	defer m.Stop()
+
+	count := func(name string) int {
+		if s := m.Streams.Find(name); s != nil {
+			return s.MessagesCount()
+		}
+		return 0
+	}
+
+	eventually(t, "history of all streams", func() bool { return count("a") == 1 && count("b") == 1 && count("c") == 1 })
+	for _, name := range []string{"a", "b", "c"} {
+		xadd(t, c, name, "2")
+	}
+	eventually(t, "new messages of all streams", func() bool { return count("a") == 2 && count("b") == 2 && count("c") == 2 })
+
+	c.Del("b")
+	c.Set("b", "not a stream", 0)
+	xadd(t, c, "a", "3")
+	xadd(t, c, "c", "3")
+	eventually(t, "new messages after key changed type", func() bool { return count("a") == 3 && count("c") == 3 })
+
+	if !m.Streams.Find("b").Deleted() {
+		t.Error("stream with key of another type is not deleted")
+	}
+}
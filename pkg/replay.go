@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Republication of a message to a stream, as a new message with its fields sorted by name and a generated ID.
+func Republication(stream string, m StreamMessage) Publication {
+	names := make([]string, 0, len(m.Content))
+	for k := range m.Content {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	p := Publication{Stream: stream}
+	for _, k := range names {
+		p.Fields = append(p.Fields, Field{Name: k, Value: fmt.Sprint(m.Content[k])})
+	}
+
+	return p
+}
+
+// Command of redis-cli adding the publication.
+func (p Publication) Command() string {
+	args := []string{"XADD", redisQuote(p.Stream)}
+	if p.MaxLen > 0 {
+		args = append(args, "MAXLEN", "~", fmt.Sprint(p.MaxLen))
+	}
+
+	id := "*"
+	if !p.ID.IsZero() {
+		id = p.ID.String()
+	}
+	args = append(args, id)
+
+	for _, f := range p.Fields {
+		args = append(args, redisQuote(f.Name), redisQuote(f.Value))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// Replay messages to a stream, publishing them in order with at most rate messages per second (no limit when zero).
+// Stops at the first failure or when context is done. Progress is invoked after each published message
+// when it is not nil. Returns number of published messages.
+func (m *Monitor) Replay(ctx context.Context, stream string, messages []StreamMessage, rate float64, progress func(n int)) (int, error) {
+	if err := m.writable(); err != nil {
+		return 0, err
+	}
+
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for i, message := range messages {
+		if i > 0 && tick != nil {
+			select {
+			case <-ctx.Done():
+				return i, ctx.Err()
+			case <-tick:
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+
+		if _, err := m.Publish(Republication(stream, message)); err != nil {
+			return i, fmt.Errorf("failed to republish message %s, err: %v", message.ID, err)
+		}
+
+		if progress != nil {
+			progress(i + 1)
+		}
+	}
+
+	return len(messages), nil
+}
@@ -0,0 +1,28 @@
+package pkg
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPublication_Command(t *testing.T) {
+	p := Republication("orders copy", StreamMessage{ID: StreamID{Ms: 1}, Content: map[string]interface{}{"name": "paid", "data": `{"id":"5"}`}})
+	want := `XADD "orders copy" * "data" "{\"id\":\"5\"}" "name" "paid"`
+	if got := p.Command(); got != want {
+		t.Errorf("Command() = %s, want %s", got, want)
+	}
+
+	p.ID, p.MaxLen = StreamID{Ms: 2}, 10
+	want = `XADD "orders copy" MAXLEN ~ 10 2-0 "data" "{\"id\":\"5\"}" "name" "paid"`
+	if got := p.Command(); got != want {
+		t.Errorf("Command() = %s, want %s", got, want)
+	}
+}
+
+func TestMonitor_Replay_readOnly(t *testing.T) {
+	m := &Monitor{ReadOnly: true}
+	n, err := m.Replay(context.Background(), "orders", []StreamMessage{{Content: map[string]interface{}{"a": "1"}}}, 0, nil)
+	if n != 0 || err != ErrReadOnly {
+		t.Errorf("Replay() = %d, %v, want 0, ErrReadOnly", n, err)
+	}
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamerEvent is a message envelope written by Laravel Streamer.
+type StreamerEvent struct {
+	// ID of the event (_id field)
+	ID string
+	// Name of the event
+	Name string
+	// Domain of the application that emitted the event
+	Domain string
+	// Created is a time when the event was emitted
+	Created time.Time
+	// Data is a decoded JSON payload of the event
+	Data interface{}
+}
+
+// StreamerEvent recognises Laravel Streamer envelope in message content.
+// Returns false when message does not have envelope fields or its data is not JSON.
+func (m *StreamMessage) StreamerEvent() (*StreamerEvent, bool) {
+	fields := make(map[string]string)
+	for _, k := range []string{"_id", "name", "domain", "created", "data"} {
+		v, ok := m.Content[k].(string)
+		if !ok && k != "domain" {
+			return nil, false
+		}
+
+		fields[k] = v
+	}
+
+	created, err := parseCreated(fields["created"])
+	if err != nil {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(fields["data"]))
+	decoder.UseNumber()
+
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, false
+	}
+
+	return &StreamerEvent{
+		ID:      fields["_id"],
+		Name:    fields["name"],
+		Domain:  fields["domain"],
+		Created: created,
+		Data:    data,
+	}, true
+}
+
+// parseCreated time of Streamer event, which is a Unix timestamp or a date time.
+func parseCreated(value string) (time.Time, error) {
+	if sec, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(0, int64(sec*float64(time.Second))), nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid created time %q", value)
+}
+
+// parseStreamerList output of `streamer:list` command, which is a table of events and their listeners.
+// Listeners of an event take one table row each, with event name only in the first one.
+func parseStreamerList(output string) map[string][]string {
+	handlers := make(map[string][]string)
+	var event string
+	for _, line := range strings.Split(output, "\n") {
+		columns := strings.Split(strings.TrimSpace(line), "|")
+		if len(columns) < 4 {
+			continue
+		}
+
+		name, listener := strings.TrimSpace(columns[1]), strings.TrimSpace(columns[2])
+		if name == "Event" && listener == "Listeners" {
+			continue
+		}
+
+		if name != "" {
+			event = name
+			if _, ok := handlers[event]; !ok {
+				handlers[event] = nil
+			}
+		}
+
+		for _, l := range strings.Split(listener, ",") {
+			l = strings.TrimSpace(l)
+			if event == "" || l == "" || strings.EqualFold(l, "none") {
+				continue
+			}
+
+			handlers[event] = append(handlers[event], l)
+		}
+	}
+
+	return handlers
+}
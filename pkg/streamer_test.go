@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamMessage_StreamerEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content map[string]interface{}
+		want    *StreamerEvent
+		wantOk  bool
+	}{
+		{"recognises envelope",
+			map[string]interface{}{"_id": "1-0", "name": "user.created", "domain": "app", "created": "1600000000", "data": `{"id":5}`},
+			&StreamerEvent{ID: "1-0", Name: "user.created", Domain: "app", Created: time.Unix(1600000000, 0), Data: map[string]interface{}{"id": json.Number("5")}},
+			true,
+		},
+		{"missing fields",
+			map[string]interface{}{"name": "user.created", "data": `{}`},
+			nil,
+			false,
+		},
+		{"data is not JSON",
+			map[string]interface{}{"_id": "1-0", "name": "user.created", "domain": "app", "created": "1600000000", "data": "text"},
+			nil,
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &StreamMessage{Content: tt.content}
+			got, ok := m.StreamerEvent()
+			if ok != tt.wantOk {
+				t.Fatalf("StreamerEvent() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StreamerEvent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseStreamerList(t *testing.T) {
+	output := `+--------------+---------------------------------+
+| Event        | Listeners                       |
++--------------+---------------------------------+
+| user.created | App\Listeners\SendWelcome       |
+|              | App\Listeners\UpdateStats       |
+| user.deleted | none                            |
+| order.paid   | App\Listeners\A, App\Listeners\B |
++--------------+---------------------------------+
+`
+	want := map[string][]string{
+		"user.created": {`App\Listeners\SendWelcome`, `App\Listeners\UpdateStats`},
+		"user.deleted": nil,
+		"order.paid":   {`App\Listeners\A`, `App\Listeners\B`},
+	}
+
+	if got := parseStreamerList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStreamerList() = %v, want %v", got, want)
+	}
+}
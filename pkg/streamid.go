@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamID identifies a message in a stream. It consists of Unix time in milliseconds
+// when message was added and a sequence number of messages added in the same millisecond.
+// Zero value is lower than any ID that Redis generates and is used as "no ID".
+type StreamID struct {
+	// Ms is a Unix time in milliseconds
+	Ms uint64
+	// Seq is a sequence number within the millisecond
+	Seq uint64
+}
+
+// ParseStreamID parses <milliseconds>-<sequence> string.
+// Sequence part may be omitted, then it equals zero.
+func ParseStreamID(s string) (StreamID, error) {
+	parts := strings.SplitN(s, "-", 2)
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return StreamID{}, fmt.Errorf("invalid stream ID %q", s)
+	}
+
+	var seq uint64
+	if len(parts) > 1 {
+		seq, err = strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return StreamID{}, fmt.Errorf("invalid stream ID %q", s)
+		}
+	}
+
+	return StreamID{Ms: ms, Seq: seq}, nil
+}
+
+// String returns ID in <milliseconds>-<sequence> format used by Redis.
+func (id StreamID) String() string {
+	return fmt.Sprintf("%d-%d", id.Ms, id.Seq)
+}
+
+// Compare returns -1 when ID is lower than the other one, 1 when it is greater and 0 when they are equal.
+func (id StreamID) Compare(other StreamID) int {
+	switch {
+	case id.Ms < other.Ms || (id.Ms == other.Ms && id.Seq < other.Seq):
+		return -1
+	case id == other:
+		return 0
+	}
+
+	return 1
+}
+
+// Less checks if ID is lower than the other one.
+func (id StreamID) Less(other StreamID) bool {
+	return id.Compare(other) < 0
+}
+
+// IsZero checks if ID is the zero value, meaning there is no ID.
+func (id StreamID) IsZero() bool {
+	return id == StreamID{}
+}
+
+// Next returns the lowest possible ID greater than this one.
+func (id StreamID) Next() StreamID {
+	if id.Seq == math.MaxUint64 {
+		return StreamID{Ms: id.Ms + 1}
+	}
+
+	return StreamID{Ms: id.Ms, Seq: id.Seq + 1}
+}
+
+// Prev returns the greatest possible ID lower than this one, or zero ID for zero ID.
+func (id StreamID) Prev() StreamID {
+	if id.Seq > 0 {
+		return StreamID{Ms: id.Ms, Seq: id.Seq - 1}
+	}
+
+	if id.Ms == 0 {
+		return StreamID{}
+	}
+
+	return StreamID{Ms: id.Ms - 1, Seq: math.MaxUint64}
+}
+
+// Time when message with this ID was added to the stream.
+func (id StreamID) Time() time.Time {
+	return time.Unix(0, 0).Add(time.Duration(id.Ms) * time.Millisecond)
+}
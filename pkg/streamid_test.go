@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestParseStreamID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    StreamID
+		wantErr bool
+	}{
+		{"full ID", "1600000000000-10", StreamID{Ms: 1600000000000, Seq: 10}, false},
+		{"milliseconds only", "1600000000000", StreamID{Ms: 1600000000000}, false},
+		{"invalid milliseconds", "foo-1", StreamID{}, true},
+		{"invalid sequence", "1-foo", StreamID{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStreamID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseStreamID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseStreamID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamID_Less(t *testing.T) {
+	ids := []StreamID{
+		{Ms: 1600000000000, Seq: 10},
+		{Ms: 999, Seq: 0},
+		{Ms: 1600000000000, Seq: 9},
+		{Ms: 1600000000001, Seq: 0},
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Less(ids[j])
+	})
+
+	want := []string{"999-0", "1600000000000-9", "1600000000000-10", "1600000000001-0"}
+	for i, id := range ids {
+		if id.String() != want[i] {
+			t.Errorf("sorted[%d] = %v, want %v", i, id, want[i])
+		}
+	}
+}
+
+func TestStreamID_NextPrev(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       StreamID
+		wantNext StreamID
+		wantPrev StreamID
+	}{
+		{"within millisecond", StreamID{Ms: 5, Seq: 3}, StreamID{Ms: 5, Seq: 4}, StreamID{Ms: 5, Seq: 2}},
+		{"first in millisecond", StreamID{Ms: 5}, StreamID{Ms: 5, Seq: 1}, StreamID{Ms: 4, Seq: 18446744073709551615}},
+		{"last in millisecond", StreamID{Ms: 5, Seq: 18446744073709551615}, StreamID{Ms: 6}, StreamID{Ms: 5, Seq: 18446744073709551614}},
+		{"zero", StreamID{}, StreamID{Seq: 1}, StreamID{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.Next(); got != tt.wantNext {
+				t.Errorf("Next() = %v, want %v", got, tt.wantNext)
+			}
+			if got := tt.id.Prev(); got != tt.wantPrev {
+				t.Errorf("Prev() = %v, want %v", got, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func TestStreamID_Time(t *testing.T) {
+	id := StreamID{Ms: 1600000000123, Seq: 5}
+	want := time.Date(2020, 9, 13, 12, 26, 40, 123000000, time.UTC)
+	if got := id.Time(); !got.Equal(want) {
+		t.Errorf("Time() = %v, want %v", got, want)
+	}
+}
@@ -3,21 +3,176 @@ package pkg
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"sync"
+	"time"
 )
 
 // Stream is a struct that holds messages of a stream and its name.
+// It is safe for concurrent use, all messages are returned as copies.
 type Stream struct {
 	// Name of the stream
-	Name          string
-	// Messages collection
-	Messages      map[string]StreamMessage
+	Name string
+	// Limit of the most recent messages kept in memory, zero means no limit.
+	// Older messages loaded on demand are kept on top of the limit.
+	Limit int
+	mu    sync.Mutex
+	// messages collection
+	messages map[StreamID]StreamMessage
+	// deleted is set when stream key no longer exists in Redis
+	deleted bool
+	// deletedAt is the time stream was found deleted
+	deletedAt time.Time
+	// order of messages IDs, from the oldest to the newest
+	order []StreamID
+	// history is a number of older messages loaded on demand
+	history int
+	// size is an approximate memory size of all messages
+	size int64
+	// groups of the stream, as last collected from Redis
+	groups []ConsumerGroup
+	// rate counts recently added messages
+	rate rateCounter
 }
 
 // AddMessage to current stream by ID and message content.
-func (s *Stream) AddMessage(id string, message map[string]interface{}) StreamMessage {
-	if s.Messages == nil {
-		s.Messages = make(map[string]StreamMessage)
+// When stream Limit is exceeded, the oldest messages are dropped.
+func (s *Stream) AddMessage(id StreamID, message map[string]interface{}) StreamMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[id]; !ok {
+		s.rate.add(id.Time(), time.Now())
+	}
+
+	streamMessage := s.insert(id, message)
+	if live := len(s.order) - s.history; s.Limit > 0 && live > s.Limit {
+		s.dropLive(live - s.Limit)
+	}
+
+	return streamMessage
+}
+
+// AddOlderMessage loaded on demand from stream history. It does not count towards stream Limit.
+func (s *Stream) AddOlderMessage(id StreamID, message map[string]interface{}) StreamMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[id]; !ok {
+		s.history++
+	}
+
+	return s.insert(id, message)
+}
+
+// DropOldest removes n oldest messages from memory. Returns number of dropped messages.
+func (s *Stream) DropOldest(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.dropOldest(n)
+}
+
+// Size returns approximate memory size of stream messages in bytes.
+func (s *Stream) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
+
+// Deleted checks if stream key no longer exists in Redis.
+// Deleted stream keeps its last known messages, all marked as evicted.
+func (s *Stream) Deleted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleted
+}
+
+// setDeleted changes deleted state of the stream, evicting all messages when it is deleted.
+// Returns false when stream already was in given state.
+func (s *Stream) setDeleted(deleted bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deleted == deleted {
+		return false
+	}
+
+	s.deleted = deleted
+	s.deletedAt = time.Time{}
+	if deleted {
+		s.deletedAt = time.Now()
+		s.evict(func(id StreamID) bool { return true })
+	}
+
+	return true
+}
+
+// deletedBefore checks if stream was found deleted before given time.
+func (s *Stream) deletedBefore(t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleted && s.deletedAt.Before(t)
+}
+
+// Groups returns consumer groups of the stream, as last collected from Redis.
+func (s *Stream) Groups() []ConsumerGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]ConsumerGroup, len(s.groups))
+	copy(groups, s.groups)
+
+	return groups
+}
+
+// setGroups of the stream. Returns false when groups did not change.
+func (s *Stream) setGroups(groups []ConsumerGroup) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if reflect.DeepEqual(s.groups, groups) {
+		return false
+	}
+
+	s.groups = groups
+
+	return true
+}
+
+// withName returns a copy of the stream with all its messages under a new name.
+func (s *Stream) withName(name string) *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	renamed := &Stream{
+		Name:      name,
+		Limit:     s.Limit,
+		messages:  make(map[StreamID]StreamMessage, len(s.messages)),
+		deleted:   s.deleted,
+		deletedAt: s.deletedAt,
+		order:     append([]StreamID(nil), s.order...),
+		history:   s.history,
+		size:      s.size,
+		groups:    s.groups,
+		rate:      s.rate,
+	}
+
+	for id, m := range s.messages {
+		renamed.messages[id] = m
+	}
+
+	return renamed
+}
+
+// insert message into collection keeping messages order.
+func (s *Stream) insert(id StreamID, message map[string]interface{}) StreamMessage {
+	if s.messages == nil {
+		s.messages = make(map[StreamID]StreamMessage)
 	}
 
 	streamMessage := StreamMessage{
@@ -25,14 +180,66 @@ func (s *Stream) AddMessage(id string, message map[string]interface{}) StreamMes
 		Content: message,
 	}
 
-	s.Messages[id] = streamMessage
+	order := s.order
+	if old, ok := s.messages[id]; ok {
+		s.size -= old.size()
+	} else {
+		i := sort.Search(len(order), func(i int) bool {
+			return !order[i].Less(id)
+		})
+		order = append(order, StreamID{})
+		copy(order[i+1:], order[i:])
+		order[i] = id
+		s.order = order
+	}
+
+	s.messages[id] = streamMessage
+	s.size += streamMessage.size()
 
 	return streamMessage
 }
 
+// dropOldest removes n oldest messages from collection, starting with older messages loaded on demand.
+func (s *Stream) dropOldest(n int) int {
+	if n > len(s.order) {
+		n = len(s.order)
+	}
+
+	s.remove(s.order[:n])
+	s.order = s.order[n:]
+	s.history -= n
+	if s.history < 0 {
+		s.history = 0
+	}
+
+	return n
+}
+
+// dropLive removes n oldest messages that were not loaded on demand, keeping older messages above them.
+func (s *Stream) dropLive(n int) {
+	live := s.order[s.history:]
+	if n > len(live) {
+		n = len(live)
+	}
+
+	s.remove(live[:n])
+	s.order = append(s.order[:s.history], live[n:]...)
+}
+
+// remove messages with given IDs from collection, without changing the order.
+func (s *Stream) remove(ids []StreamID) {
+	for _, id := range ids {
+		s.size -= s.messages[id].size()
+		delete(s.messages, id)
+	}
+}
+
 // GetMessage from stream messages collection by ID.
-func (s *Stream) GetMessage(ID string) (*StreamMessage, error) {
-	m, ok := s.Messages[ID]
+func (s *Stream) GetMessage(ID StreamID) (*StreamMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.messages[ID]
 	if !ok {
 		return nil, errors.New(fmt.Sprintf("there are no messages with given ID: %s", ID))
 	}
@@ -40,29 +247,122 @@ func (s *Stream) GetMessage(ID string) (*StreamMessage, error) {
 	return &m, nil
 }
 
-// GetMessagesList returns array of messages IDs.
-func (s *Stream) GetMessagesList() []string {
-	var list []string
-	for _, m := range s.Messages {
-		list = append(list, m.ID)
+// GetMessagesList returns array of messages IDs, from the oldest to the newest.
+func (s *Stream) GetMessagesList() []StreamID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.order
+	if len(order) == 0 {
+		return nil
 	}
 
-	sort.Strings(list)
+	list := make([]StreamID, len(order))
+	copy(list, order)
 
 	return list
 }
 
 // MessagesCount returns how many messages are in a stream.
 func (s *Stream) MessagesCount() int {
-	return len(s.Messages)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.messages)
+}
+
+// FirstID returns ID of the oldest message that was not evicted,
+// or zero ID when there is no such message.
+func (s *Stream) FirstID() StreamID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range s.order {
+		if !s.messages[id].Evicted {
+			return id
+		}
+	}
+
+	return StreamID{}
+}
+
+// LastID returns ID of the newest message that was not evicted,
+// or zero ID when there is no such message.
+func (s *Stream) LastID() StreamID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.order
+	for i := len(order) - 1; i >= 0; i-- {
+		if !s.messages[order[i]].Evicted {
+			return order[i]
+		}
+	}
+
+	return StreamID{}
+}
+
+// EvictBefore marks all messages older than given ID as evicted,
+// which means they were trimmed from the stream in Redis.
+// Returns IDs of newly evicted messages.
+func (s *Stream) EvictBefore(id StreamID) []StreamID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.evict(func(i StreamID) bool {
+		return i.Less(id)
+	})
+}
+
+// EvictAll marks all messages of the stream as evicted.
+// Returns IDs of newly evicted messages.
+func (s *Stream) EvictAll() []StreamID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.evict(func(id StreamID) bool { return true })
+}
+
+// evict messages matching given filter.
+func (s *Stream) evict(filter func(id StreamID) bool) []StreamID {
+	var evicted []StreamID
+	for i, m := range s.messages {
+		if m.Evicted || !filter(i) {
+			continue
+		}
+
+		m.Evicted = true
+		s.messages[i] = m
+		evicted = append(evicted, i)
+	}
+
+	return evicted
 }
 
 // StreamMessage with ID and Content
 type StreamMessage struct {
 	// ID of the message
-	ID      string
+	ID StreamID
 	// Content of the message
 	Content map[string]interface{}
+	// Evicted is set when message is no longer in Redis stream,
+	// because the stream was trimmed, deleted or recreated.
+	Evicted bool
+}
+
+// size returns approximate memory size of the message in bytes.
+func (m StreamMessage) size() int64 {
+	size := int64(16)
+	for k, v := range m.Content {
+		size += int64(len(k))
+		if str, ok := v.(string); ok {
+			size += int64(len(str))
+		} else {
+			size += int64(len(fmt.Sprint(v)))
+		}
+	}
+
+	return size
 }
 
 // ParseContent transforms message content (which is a map[string]interface{})
@@ -84,18 +384,30 @@ func (m *StreamMessage) ParseContent() string {
 	return content
 }
 
-// Streams holds collection of streams.
+// Streams holds collection of streams. It is safe for concurrent use.
 type Streams struct {
+	mu         sync.RWMutex
 	collection map[string]*Stream
 }
 
-// All returns collection of currently stored streams.
+// All returns a copy of collection of currently stored streams.
 func (s *Streams) All() map[string]*Stream {
-	return s.collection
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make(map[string]*Stream, len(s.collection))
+	for k, v := range s.collection {
+		all[k] = v
+	}
+
+	return all
 }
 
 // Push stream to collection.
 func (s *Streams) Push(stream *Stream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.collection == nil {
 		s.collection = make(map[string]*Stream)
 	}
@@ -109,6 +421,9 @@ func (s *Streams) Push(stream *Stream) {
 
 // Find returns stream by key (name of the stream).
 func (s *Streams) Find(key string) *Stream {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	stream, ok := s.collection[key]
 
 	if !ok {
@@ -116,4 +431,40 @@ func (s *Streams) Find(key string) *Stream {
 	}
 
 	return stream
-}
\ No newline at end of file
+}
+
+// Remove stream from collection by key (name of the stream).
+// Returns removed stream or nil when there was no such stream.
+func (s *Streams) Remove(key string) *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.collection[key]
+	if !ok {
+		return nil
+	}
+
+	delete(s.collection, key)
+
+	return stream
+}
+
+// Rename moves stream stored under old key to the new one.
+// Renamed stream is a new Stream with all messages of the old one.
+// Stream previously stored under new key is replaced.
+// Returns renamed stream or nil when there was no stream under old key.
+func (s *Streams) Rename(old, new string) *Stream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream, ok := s.collection[old]
+	if !ok {
+		return nil
+	}
+
+	delete(s.collection, old)
+	renamed := stream.withName(new)
+	s.collection[new] = renamed
+
+	return renamed
+}
@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -14,7 +15,7 @@ func TestStreams_Find(t *testing.T) {
 	}
 	stream := &Stream{
 		Name:     "Stream",
-		Messages: nil,
+		messages: nil,
 	}
 	tests := []struct {
 		name   string
@@ -66,7 +67,7 @@ func TestStreams_Push(t *testing.T) {
 			"Stream",
 			args{stream: &Stream{
 				Name:     "Stream",
-				Messages: nil,
+				messages: nil,
 			}},
 			1,
 		},
@@ -74,13 +75,13 @@ func TestStreams_Push(t *testing.T) {
 			fields{Collection: map[string]*Stream{
 				"Stream": {
 					Name:     "Stream",
-					Messages: nil,
+					messages: nil,
 				},
 			}},
 			"Stream",
 			args{stream: &Stream{
 				Name:     "Stream",
-				Messages: nil,
+				messages: nil,
 			}},
 			1,
 		},
@@ -105,7 +106,7 @@ func TestStreams_Push(t *testing.T) {
 
 func TestStream_AddMessage(t *testing.T) {
 	type args struct {
-		id      string
+		id      StreamID
 		message map[string]interface{}
 	}
 	tests := []struct {
@@ -114,10 +115,10 @@ func TestStream_AddMessage(t *testing.T) {
 		want StreamMessage
 	}{
 		{"new message added", args{
-			id:      "1",
+			id:      StreamID{Ms: 1},
 			message: map[string]interface{}{"foo": "bar"},
 		}, StreamMessage{
-			ID:      "1",
+			ID:      StreamID{Ms: 1},
 			Content: map[string]interface{}{"foo": "bar"},
 		}},
 	}
@@ -125,7 +126,7 @@ func TestStream_AddMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Stream{
 				Name:     "Stream",
-				Messages: nil,
+				messages: nil,
 			}
 			if got := s.AddMessage(tt.args.id, tt.args.message); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("AddMessage() = %v, want %v", got, tt.want)
@@ -137,13 +138,13 @@ func TestStream_AddMessage(t *testing.T) {
 func TestStream_GetMessage(t *testing.T) {
 	type fields struct {
 		Name     string
-		Messages map[string]StreamMessage
+		Messages map[StreamID]StreamMessage
 	}
 	type args struct {
-		ID string
+		ID StreamID
 	}
 	message := &StreamMessage{
-		ID:      "1",
+		ID:      StreamID{Ms: 1},
 		Content: map[string]interface{}{"foo": "bar"},
 	}
 	tests := []struct {
@@ -155,25 +156,22 @@ func TestStream_GetMessage(t *testing.T) {
 	}{
 		{
 			"finds message",
-			fields{Name: "Stream", Messages: map[string]StreamMessage{"1": *message}},
-			args{ID: "1"},
+			fields{Name: "Stream", Messages: map[StreamID]StreamMessage{StreamID{Ms: 1}: *message}},
+			args{ID: StreamID{Ms: 1}},
 			message,
 			false,
 		},
 		{
 			"fails to find message",
-			fields{Name: "Stream", Messages: map[string]StreamMessage{"1": *message}},
-			args{ID: "2"},
+			fields{Name: "Stream", Messages: map[StreamID]StreamMessage{StreamID{Ms: 1}: *message}},
+			args{ID: StreamID{Ms: 2}},
 			nil,
 			true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &Stream{
-				Name:     tt.fields.Name,
-				Messages: tt.fields.Messages,
-			}
+			s := testStream(tt.fields.Name, tt.fields.Messages)
 			got, err := s.GetMessage(tt.args.ID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetMessage() error = %v, wantErr %v", err, tt.wantErr)
@@ -189,48 +187,45 @@ func TestStream_GetMessage(t *testing.T) {
 func TestStream_GetMessagesList(t *testing.T) {
 	type fields struct {
 		Name     string
-		Messages map[string]StreamMessage
+		Messages map[StreamID]StreamMessage
 	}
 	tests := []struct {
 		name   string
 		fields fields
-		want   []string
+		want   []StreamID
 	}{
 		{"single message on the list",
 			fields{
 				Name: "Stream",
-				Messages: map[string]StreamMessage{
-					"1": {
-						ID:      "1",
+				Messages: map[StreamID]StreamMessage{
+					StreamID{Ms: 1}: {
+						ID:      StreamID{Ms: 1},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
 				},
 			},
-			[]string{"1"},
+			[]StreamID{StreamID{Ms: 1}},
 		},
 		{"multiple messages on the list",
 			fields{
 				Name: "Stream",
-				Messages: map[string]StreamMessage{
-					"1": {
-						ID:      "1",
+				Messages: map[StreamID]StreamMessage{
+					StreamID{Ms: 1}: {
+						ID:      StreamID{Ms: 1},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
-					"2": {
-						ID:      "2",
+					StreamID{Ms: 2}: {
+						ID:      StreamID{Ms: 2},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
 				},
 			},
-			[]string{"1", "2"},
+			[]StreamID{StreamID{Ms: 1}, StreamID{Ms: 2}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &Stream{
-				Name:     tt.fields.Name,
-				Messages: tt.fields.Messages,
-			}
+			s := testStream(tt.fields.Name, tt.fields.Messages)
 			if got := s.GetMessagesList(); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetMessagesList() = %v, want %v", got, tt.want)
 			}
@@ -241,7 +236,7 @@ func TestStream_GetMessagesList(t *testing.T) {
 func TestStream_MessagesCount(t *testing.T) {
 	type fields struct {
 		Name     string
-		Messages map[string]StreamMessage
+		Messages map[StreamID]StreamMessage
 	}
 	tests := []struct {
 		name   string
@@ -251,9 +246,9 @@ func TestStream_MessagesCount(t *testing.T) {
 		{"one message",
 			fields{
 				Name: "Stream",
-				Messages: map[string]StreamMessage{
-					"1": {
-						ID:      "1",
+				Messages: map[StreamID]StreamMessage{
+					StreamID{Ms: 1}: {
+						ID:      StreamID{Ms: 1},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
 				},
@@ -263,17 +258,17 @@ func TestStream_MessagesCount(t *testing.T) {
 		{"mane messages",
 			fields{
 				Name: "Stream",
-				Messages: map[string]StreamMessage{
-					"1": {
-						ID:      "1",
+				Messages: map[StreamID]StreamMessage{
+					StreamID{Ms: 1}: {
+						ID:      StreamID{Ms: 1},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
-					"2": {
-						ID:      "2",
+					StreamID{Ms: 2}: {
+						ID:      StreamID{Ms: 2},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
-					"3": {
-						ID:      "3",
+					StreamID{Ms: 3}: {
+						ID:      StreamID{Ms: 3},
 						Content: map[string]interface{}{"foo": "bar"},
 					},
 				},
@@ -283,10 +278,7 @@ func TestStream_MessagesCount(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &Stream{
-				Name:     tt.fields.Name,
-				Messages: tt.fields.Messages,
-			}
+			s := testStream(tt.fields.Name, tt.fields.Messages)
 			if got := s.MessagesCount(); got != tt.want {
 				t.Errorf("MessagesCount() = %v, want %v", got, tt.want)
 			}
@@ -296,7 +288,7 @@ func TestStream_MessagesCount(t *testing.T) {
 
 func TestStreamMessage_ParseContent(t *testing.T) {
 	type fields struct {
-		ID      string
+		ID      StreamID
 		Content map[string]interface{}
 	}
 	tests := []struct {
@@ -305,11 +297,11 @@ func TestStreamMessage_ParseContent(t *testing.T) {
 		want   string
 	}{
 		{"simple content", fields{
-			ID:      "1",
+			ID:      StreamID{Ms: 1},
 			Content: map[string]interface{}{"foo": "bar"},
 		}, "Field: foo\r\nValue: bar\r\n\r\n"},
 		{"complex content ordered", fields{
-			ID:      "1",
+			ID:      StreamID{Ms: 1},
 			Content: map[string]interface{}{"b": "second", "a": "first", "c": "third"},
 		}, "Field: a\r\nValue: first\r\n\r\nField: b\r\nValue: second\r\n\r\nField: c\r\nValue: third\r\n\r\n"},
 	}
@@ -325,3 +317,203 @@ func TestStreamMessage_ParseContent(t *testing.T) {
 		})
 	}
 }
+
+func TestStream_EvictBefore(t *testing.T) {
+	tests := []struct {
+		name        string
+		messages    map[StreamID]StreamMessage
+		id          StreamID
+		wantEvicted int
+		wantFirstID StreamID
+	}{
+		{"evicts older messages",
+			map[StreamID]StreamMessage{
+				StreamID{Ms: 1, Seq: 9}:  {ID: StreamID{Ms: 1, Seq: 9}},
+				StreamID{Ms: 1, Seq: 10}: {ID: StreamID{Ms: 1, Seq: 10}},
+				StreamID{Ms: 2}:          {ID: StreamID{Ms: 2}},
+			},
+			StreamID{Ms: 1, Seq: 10},
+			1,
+			StreamID{Ms: 1, Seq: 10},
+		},
+		{"skips already evicted messages",
+			map[StreamID]StreamMessage{
+				StreamID{Ms: 1}: {ID: StreamID{Ms: 1}, Evicted: true},
+				StreamID{Ms: 2}: {ID: StreamID{Ms: 2}},
+				StreamID{Ms: 3}: {ID: StreamID{Ms: 3}},
+			},
+			StreamID{Ms: 3},
+			1,
+			StreamID{Ms: 3},
+		},
+		{"evicts nothing when first message is newer",
+			map[StreamID]StreamMessage{
+				StreamID{Ms: 5}: {ID: StreamID{Ms: 5}},
+			},
+			StreamID{Ms: 1},
+			0,
+			StreamID{Ms: 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := testStream("Stream", tt.messages)
+			if got := s.EvictBefore(tt.id); len(got) != tt.wantEvicted {
+				t.Errorf("EvictBefore() = %v, want %v evicted", got, tt.wantEvicted)
+			}
+			if got := s.FirstID(); got != tt.wantFirstID {
+				t.Errorf("FirstID() = %v, want %v", got, tt.wantFirstID)
+			}
+		})
+	}
+}
+
+func TestStream_EvictAll(t *testing.T) {
+	s := testStream("Stream", map[StreamID]StreamMessage{
+		StreamID{Ms: 1}: {ID: StreamID{Ms: 1}},
+		StreamID{Ms: 2}: {ID: StreamID{Ms: 2}},
+	})
+
+	if got := s.EvictAll(); len(got) != 2 {
+		t.Errorf("EvictAll() = %v, want 2 evicted", got)
+	}
+	if got := s.LastID(); !got.IsZero() {
+		t.Errorf("LastID() = %v, want empty", got)
+	}
+	if got := s.MessagesCount(); got != 2 {
+		t.Errorf("MessagesCount() = %v, want 2", got)
+	}
+}
+
+func TestStream_AddMessageLimit(t *testing.T) {
+	s := &Stream{Name: "Stream", Limit: 2}
+	s.AddMessage(StreamID{Ms: 1}, map[string]interface{}{"foo": "bar"})
+	s.AddMessage(StreamID{Ms: 2}, map[string]interface{}{"foo": "bar"})
+	s.AddMessage(StreamID{Ms: 3}, map[string]interface{}{"foo": "bar"})
+
+	if got := s.GetMessagesList(); !reflect.DeepEqual(got, []StreamID{StreamID{Ms: 2}, StreamID{Ms: 3}}) {
+		t.Errorf("GetMessagesList() = %v, want %v", got, []StreamID{StreamID{Ms: 2}, StreamID{Ms: 3}})
+	}
+
+	s.AddOlderMessage(StreamID{Ms: 1}, map[string]interface{}{"foo": "bar"})
+	s.AddMessage(StreamID{Ms: 4}, map[string]interface{}{"foo": "bar"})
+	if got := s.GetMessagesList(); !reflect.DeepEqual(got, []StreamID{StreamID{Ms: 1}, StreamID{Ms: 3}, StreamID{Ms: 4}}) {
+		t.Errorf("GetMessagesList() = %v, want %v", got, []StreamID{StreamID{Ms: 1}, StreamID{Ms: 3}, StreamID{Ms: 4}})
+	}
+
+	if got := s.DropOldest(5); got != 3 {
+		t.Errorf("DropOldest() = %v, want %v", got, 3)
+	}
+	if got := s.Size(); got != 0 {
+		t.Errorf("Size() = %v, want %v", got, 0)
+	}
+}
+
+func TestStream_AddMessageHistory(t *testing.T) {
+	s := &Stream{Name: "Stream", Limit: 5}
+	for i := 11; i <= 15; i++ {
+		s.AddMessage(StreamID{Ms: uint64(i)}, map[string]interface{}{"foo": "bar"})
+	}
+	for i := 10; i >= 1; i-- {
+		s.AddOlderMessage(StreamID{Ms: uint64(i)}, map[string]interface{}{"foo": "bar"})
+	}
+
+	for i := 16; i <= 25; i++ {
+		s.AddMessage(StreamID{Ms: uint64(i)}, map[string]interface{}{"foo": "bar"})
+		if got := s.MessagesCount(); got != 15 {
+			t.Fatalf("MessagesCount() after live message %d = %v, want %v", i, got, 15)
+		}
+	}
+
+	list := s.GetMessagesList()
+	if list[9] != (StreamID{Ms: 10}) || list[10] != (StreamID{Ms: 21}) {
+		t.Errorf("GetMessagesList() = %v, want older messages 1-10 and live messages 21-25", list)
+	}
+
+	if got := s.DropOldest(12); got != 12 || s.history != 0 {
+		t.Errorf("DropOldest() = %v with %v older messages left, want %v with none", got, s.history, 12)
+	}
+
+	s.AddMessage(StreamID{Ms: 26}, map[string]interface{}{"foo": "bar"})
+	if got := s.GetMessagesList(); !reflect.DeepEqual(got, []StreamID{{Ms: 23}, {Ms: 24}, {Ms: 25}, {Ms: 26}}) {
+		t.Errorf("GetMessagesList() = %v, want live messages 23-26", got)
+	}
+}
+
+func TestStream_ConcurrentAccess(t *testing.T) {
+	s := &Stream{Name: "Stream", Limit: 50}
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 200; i++ {
+			s.AddMessage(StreamID{Ms: uint64(i)}, map[string]interface{}{"foo": "bar"})
+		}
+		s.EvictBefore(StreamID{Ms: 100})
+		done <- true
+	}()
+
+	go func() {
+		for i := 0; i < 200; i++ {
+			for _, id := range s.GetMessagesList() {
+				_, _ = s.GetMessage(id)
+			}
+			_ = s.MessagesCount()
+			_ = s.LastID()
+			_ = s.Size()
+		}
+		done <- true
+	}()
+
+	<-done
+	<-done
+
+	if got := s.MessagesCount(); got != 50 {
+		t.Errorf("MessagesCount() = %v, want %v", got, 50)
+	}
+}
+
+func TestStreams_ConcurrentAccess(t *testing.T) {
+	s := &Streams{}
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			name := fmt.Sprintf("stream-%d", i)
+			s.Push(&Stream{Name: name})
+			if i%2 == 0 {
+				s.Rename(name, fmt.Sprintf("renamed-%d", i))
+			}
+		}
+		done <- true
+	}()
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			_ = s.Find(fmt.Sprintf("stream-%d", i))
+			for _, stream := range s.All() {
+				_ = stream.MessagesCount()
+			}
+		}
+		done <- true
+	}()
+
+	<-done
+	<-done
+
+	if got := len(s.All()); got != 100 {
+		t.Errorf("len(All()) = %v, want %v", got, 100)
+	}
+}
+
+// testStream with given messages, keeping their evicted state.
+func testStream(name string, messages map[StreamID]StreamMessage) *Stream {
+	s := &Stream{Name: name}
+	for id, m := range messages {
+		s.AddMessage(id, m.Content)
+		if m.Evicted {
+			s.messages[id] = m
+		}
+	}
+
+	return s
+}
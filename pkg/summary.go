@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"github.com/go-redis/redis"
+	"sort"
+	"time"
+)
+
+// Summary of all streams known to Monitor.
+type Summary struct {
+	// Streams is a number of streams that exist in Redis
+	Streams int
+	// Entries is a total length (XLEN) of all streams
+	Entries int64
+	// Memory is a total memory used by all stream keys (MEMORY USAGE), in bytes
+	Memory int64
+	// TopRates are streams with the highest rate of messages in the last minute
+	TopRates []StreamRate
+	// TopPending are consumer groups with the most pending entries
+	TopPending []GroupPending
+}
+
+// StreamRate holds messages per second of a stream.
+type StreamRate struct {
+	Stream string
+	Rate   float64
+}
+
+// GroupPending holds number of pending entries of a consumer group.
+type GroupPending struct {
+	Stream  string
+	Group   string
+	Pending int64
+}
+
+// Summary reads length and memory usage of all streams from Redis and summarises them
+// together with streams throughput and consumer groups, listing up to top streams and groups.
+func (m *Monitor) Summary(top int) (Summary, error) {
+	var streams []*Stream
+	for _, s := range m.Streams.All() {
+		if !s.Deleted() {
+			streams = append(streams, s)
+		}
+	}
+
+	sort.Slice(streams, func(i, j int) bool {
+		return streams[i].Name < streams[j].Name
+	})
+
+	summary := Summary{Streams: len(streams)}
+	if len(streams) == 0 {
+		return summary, nil
+	}
+
+	pipe := m.Redis.Pipeline()
+	defer pipe.Close()
+
+	now := time.Now()
+	var lengths, memory []*redis.IntCmd
+	for _, s := range streams {
+		lengths = append(lengths, pipe.XLen(s.Name))
+		memory = append(memory, pipe.MemoryUsage(s.Name))
+		summary.TopRates = append(summary.TopRates, StreamRate{Stream: s.Name, Rate: s.Metrics(now).Rate1m})
+		for _, g := range s.Groups() {
+			summary.TopPending = append(summary.TopPending, GroupPending{Stream: s.Name, Group: g.Name, Pending: g.Pending})
+		}
+	}
+
+	// Memory usage of keys deleted in the meantime or when MEMORY command
+	// is not available fails single commands, leaving it at zero.
+	_, _ = pipe.Exec()
+	for i := range streams {
+		if err := lengths[i].Err(); err != nil {
+			return Summary{}, err
+		}
+
+		summary.Entries += lengths[i].Val()
+		summary.Memory += memory[i].Val()
+	}
+
+	sort.SliceStable(summary.TopRates, func(i, j int) bool {
+		return summary.TopRates[i].Rate > summary.TopRates[j].Rate
+	})
+	sort.SliceStable(summary.TopPending, func(i, j int) bool {
+		return summary.TopPending[i].Pending > summary.TopPending[j].Pending
+	})
+
+	if len(summary.TopRates) > top {
+		summary.TopRates = summary.TopRates[:top]
+	}
+	if len(summary.TopPending) > top {
+		summary.TopPending = summary.TopPending[:top]
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+	"unicode/utf8"
+)
+
+// matchesLimit is the highest number of watch matches kept by Monitor, the oldest are dropped first.
+const matchesLimit = 1000
+
+// watchCommandsLimit is the highest number of watch commands running at once.
+const watchCommandsLimit = 4
+
+// watchCommandTimeout is the longest time a watch command can run.
+const watchCommandTimeout = time.Second * 30
+
+// Watch is a filter evaluated on every new message read by Monitor, messages loaded from history are not watched.
+type Watch struct {
+	// Query of the filter, see Filter for its syntax
+	Query  string
+	filter *Filter
+}
+
+// WatchMatch is a new message matching a watch.
+type WatchMatch struct {
+	Watch   *Watch
+	Stream  string
+	Message StreamMessage
+	// Time when the message was matched
+	Time time.Time
+}
+
+// messageDocument is a JSON representation of a stream message.
+// Values that are not valid UTF-8 are written as {"base64": "..."} objects, so no bytes are lost.
+type messageDocument struct {
+	Stream string                 `json:"stream"`
+	ID     string                 `json:"id"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// binaryValue is a JSON representation of a field value that is not valid UTF-8.
+type binaryValue struct {
+	Base64 string `json:"base64"`
+}
+
+// newMessageDocument of a stream message.
+func newMessageDocument(stream string, m StreamMessage) messageDocument {
+	fields := make(map[string]interface{}, len(m.Content))
+	for k, v := range m.Content {
+		if s, ok := v.(string); ok && !utf8.ValidString(s) {
+			v = binaryValue{Base64: base64.StdEncoding.EncodeToString([]byte(s))}
+		}
+
+		fields[k] = v
+	}
+
+	return messageDocument{Stream: stream, ID: m.ID.String(), Fields: fields}
+}
+
+// AddWatch of messages matching filter query. Fields values are decoded with Monitor Decoders before matching.
+func (m *Monitor) AddWatch(query string) (*Watch, error) {
+	f, err := ParseFilter(query, m.Decoders)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watch{Query: query, filter: f}
+	m.watchMu.Lock()
+	m.watches = append(m.watches, w)
+	m.watchMu.Unlock()
+
+	return w, nil
+}
+
+// RemoveWatch so that it is no longer evaluated. Its past matches are kept.
+func (m *Monitor) RemoveWatch(w *Watch) {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+
+	for i, watch := range m.watches {
+		if watch == w {
+			m.watches = append(m.watches[:i], m.watches[i+1:]...)
+			return
+		}
+	}
+}
+
+// Watches returns a copy of currently evaluated watches, in order they were added.
+func (m *Monitor) Watches() []*Watch {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+
+	return append([]*Watch(nil), m.watches...)
+}
+
+// Matches returns a copy of collected watch matches, from the oldest.
+func (m *Monitor) Matches() []WatchMatch {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+
+	return append([]WatchMatch(nil), m.matches...)
+}
+
+// OnWatchMatch assigns handlers that should be invoked when a new message matches a watch.
+func (m *Monitor) OnWatchMatch(handler func(match WatchMatch)) {
+	m.matchHandlers = append(m.matchHandlers, handler)
+}
+
+// checkWatches against a new message, collecting matches and running WatchCommand for each of them.
+// Matches are not queued for WatchCommand, they are skipped with a warning when watchCommandsLimit commands are running.
+func (m *Monitor) checkWatches(stream *Stream, message StreamMessage) {
+	if m.isCopy(stream.Name) {
+		return
+	}
+
+	var matches []WatchMatch
+	for _, w := range m.Watches() {
+		if w.filter.Match(stream.Name, message) {
+			matches = append(matches, WatchMatch{Watch: w, Stream: stream.Name, Message: message, Time: time.Now()})
+		}
+	}
+
+	if len(matches) == 0 {
+		return
+	}
+
+	m.watchMu.Lock()
+	m.matches = append(m.matches, matches...)
+	if len(m.matches) > matchesLimit {
+		m.matches = append([]WatchMatch(nil), m.matches[len(m.matches)-matchesLimit:]...)
+	}
+	if m.watchSlots == nil {
+		m.watchSlots = make(chan struct{}, watchCommandsLimit)
+	}
+	slots := m.watchSlots
+	m.watchMu.Unlock()
+
+	for _, match := range matches {
+		for _, l := range m.matchHandlers {
+			l(match)
+		}
+
+		if m.WatchCommand == "" {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+			match := match
+			m.run(func() {
+				defer func() { <-slots }()
+				m.runWatchCommand(match)
+			})
+		default:
+			LogWarning(fmt.Sprintf("watch command skipped for message %s of %s stream, %d commands already running", match.Message.ID, match.Stream, watchCommandsLimit))
+		}
+	}
+}
+
+// runWatchCommand with shell, passing matched message as JSON on its standard input.
+// Stream name, message ID and watch query are passed in SWARM_STREAM, SWARM_ID and SWARM_WATCH variables.
+// Command is killed after watchCommandTimeout or when monitor is stopped.
+func (m *Monitor) runWatchCommand(match WatchMatch) {
+	input, err := json.Marshal(newMessageDocument(match.Stream, match.Message))
+	if err != nil {
+		LogWarning(fmt.Sprintf("failed to encode message %s of %s stream for watch command, err: %v", match.Message.ID, match.Stream, err))
+		return
+	}
+
+	parent := m.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, watchCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", m.WatchCommand)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"SWARM_STREAM="+match.Stream,
+		"SWARM_ID="+match.Message.ID.String(),
+		"SWARM_WATCH="+match.Watch.Query,
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		LogWarning(fmt.Sprintf("watch command failed for message %s of %s stream, err: %v, output: %s", match.Message.ID, match.Stream, err, out))
+	}
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMonitor_checkWatches(t *testing.T) {
+	m := &Monitor{Streams: &Streams{}}
+	w, err := m.AddWatch("order_id=123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []WatchMatch
+	m.OnWatchMatch(func(match WatchMatch) {
+		got = append(got, match)
+	})
+
+	s := &Stream{Name: "orders"}
+	m.checkWatches(s, StreamMessage{ID: StreamID{Ms: 1}, Content: map[string]interface{}{"order_id": "122"}})
+	m.checkWatches(s, StreamMessage{ID: StreamID{Ms: 2}, Content: map[string]interface{}{"order_id": "123"}})
+
+	if len(got) != 1 || got[0].Watch != w || got[0].Stream != "orders" || got[0].Message.ID != (StreamID{Ms: 2}) {
+		t.Fatalf("OnWatchMatch() got %v, want one match of message 2-0", got)
+	}
+
+	if matches := m.Matches(); len(matches) != 1 {
+		t.Errorf("Matches() = %v, want one match", matches)
+	}
+
+	m.RemoveWatch(w)
+	m.checkWatches(s, StreamMessage{ID: StreamID{Ms: 3}, Content: map[string]interface{}{"order_id": "123"}})
+	if len(got) != 1 || len(m.Watches()) != 0 {
+		t.Errorf("removed watch matched message, matches: %v", got)
+	}
+}
+
+func TestMonitor_runWatchCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swarm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out.json")
+	m := &Monitor{WatchCommand: "cat > " + out}
+	m.runWatchCommand(WatchMatch{
+		Watch:   &Watch{Query: "order_id=123"},
+		Stream:  "orders",
+		Message: StreamMessage{ID: StreamID{Ms: 2}, Content: map[string]interface{}{"order_id": "123"}},
+		Time:    time.Now(),
+	})
+
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("watch command did not run, err: %v", err)
+	}
+
+	var got messageDocument
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := messageDocument{Stream: "orders", ID: "2-0", Fields: map[string]interface{}{"order_id": "123"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("watch command input = %v, want %v", got, want)
+	}
+}
+
+func TestMonitor_checkWatchesCommands(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Monitor{Streams: &Streams{}, WatchCommand: "exec sleep 30"}
+	m.ctx, m.cancel = ctx, cancel
+	if _, err := m.AddWatch("order_id=123"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Stream{Name: "orders"}
+	for i := 0; i < watchCommandsLimit*2; i++ {
+		m.checkWatches(s, StreamMessage{ID: StreamID{Ms: uint64(i + 1)}, Content: map[string]interface{}{"order_id": "123"}})
+	}
+
+	if running := len(m.watchSlots); running != watchCommandsLimit {
+		t.Errorf("checkWatches() started %d commands, want %d", running, watchCommandsLimit)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Stop() did not kill running watch commands")
+	}
+
+	if running := len(m.watchSlots); running != 0 {
+		t.Errorf("%d watch commands still running after Stop()", running)
+	}
+}